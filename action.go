@@ -0,0 +1,328 @@
+package taskscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ActionType is the type of an Action as defined by TASK_ACTION_TYPE
+type ActionType int32
+
+// Action types of Task Scheduler 2.0
+const (
+	ActionTypeExec        ActionType = 0
+	ActionTypeComHandler  ActionType = 5
+	ActionTypeEmail       ActionType = 6
+	ActionTypeShowMessage ActionType = 7
+)
+
+// String returns the name of the action type, e.g. "Exec"
+func (a ActionType) String() string {
+	switch a {
+	case ActionTypeExec:
+		return "Exec"
+	case ActionTypeComHandler:
+		return "ComHandler"
+	case ActionTypeEmail:
+		return "Email"
+	case ActionTypeShowMessage:
+		return "ShowMessage"
+	}
+	return "Unknown"
+}
+
+// MarshalJSON encodes the action type as its name, e.g. "Exec"
+func (a ActionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON decodes the action type from its name or number
+func (a *ActionType) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "action type", int(ActionTypeShowMessage), func(i int) string { return ActionType(i).String() })
+	*a = ActionType(n)
+	return err
+}
+
+// Action is an action defined in a scheduled Task. Use a type switch to get the
+// concrete action, e.g. ExecAction.
+type Action interface {
+	// Type returns the TASK_ACTION_TYPE of the action
+	Type() ActionType
+}
+
+// Actions are the actions of a Task. They are encoded in JSON with their type, e.g.
+// "type": "Exec", so the concrete actions are decoded again. A RawAction is encoded with its
+// raw properties, including the number of its type.
+type Actions []Action
+
+// actionTypes are the concrete actions by type, they are decoded by Actions
+var actionTypes = map[ActionType]Action{
+	ActionTypeExec:        ExecAction{},
+	ActionTypeComHandler:  ComHandlerAction{},
+	ActionTypeEmail:       EmailAction{},
+	ActionTypeShowMessage: ShowMessageAction{},
+}
+
+// MarshalJSON encodes the actions with their types
+func (actions Actions) MarshalJSON() ([]byte, error) {
+	if actions == nil {
+		return []byte("null"), nil
+	}
+	list := make([]json.RawMessage, len(actions))
+	for i, a := range actions {
+		var err error
+		switch a := a.(type) {
+		case nil:
+			list[i] = json.RawMessage("null")
+		case RawAction:
+			list[i], err = json.Marshal(a)
+		default:
+			list[i], err = marshalWithType(a.Type().String(), a)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(list)
+}
+
+// UnmarshalJSON decodes the actions into their concrete types. Actions of unsupported types
+// are decoded as RawAction with the JSON types of their properties, e.g. float64 for numbers.
+func (actions *Actions) UnmarshalJSON(data []byte) error {
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	if list == nil {
+		*actions = nil
+		return nil
+	}
+	decoded := make(Actions, len(list))
+	for i, raw := range list {
+		var err error
+		if decoded[i], err = unmarshalAction(raw); err != nil {
+			return fmt.Errorf("Could not decode action %d: %w", i+1, err)
+		}
+	}
+	*actions = decoded
+	return nil
+}
+
+func unmarshalAction(data []byte) (Action, error) {
+	if string(data) == "null" {
+		return nil, nil
+	}
+	typeField, err := typeOf(data)
+	if err != nil {
+		return nil, err
+	}
+	var actionType ActionType
+	if err := json.Unmarshal(typeField, &actionType); err != nil {
+		return nil, err
+	}
+	prototype, ok := actionTypes[actionType]
+	if !ok {
+		raw := RawAction{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		raw["type"] = int32(actionType)
+		return raw, nil
+	}
+	a := reflect.New(reflect.TypeOf(prototype))
+	if err := json.Unmarshal(data, a.Interface()); err != nil {
+		return nil, err
+	}
+	return a.Elem().Interface().(Action), nil
+}
+
+// Actions returns all actions of the Task in their defined order. If AllActions is not
+// set, e.g. for a Task that was not read from the Task Scheduler, ActionList is returned.
+func (t Task) Actions() Actions {
+	if t.AllActions != nil || t.ActionList == nil {
+		return t.AllActions
+	}
+	actions := make(Actions, len(t.ActionList))
+	for i, a := range t.ActionList {
+		actions[i] = a
+	}
+	return actions
+}
+
+// ExecAction is an action defined in a scheduled Task if type IExecAction.
+type ExecAction struct {
+	WorkingDirectory string `json:"workingDirectory"` // empty if the action has no working directory
+	Path             string `json:"path"`
+	Arguments        string `json:"arguments"`
+}
+
+// Type returns ActionTypeExec
+func (ExecAction) Type() ActionType { return ActionTypeExec }
+
+// ExpandedPath returns Path with environment variables like %SystemRoot% replaced by their values
+// in the current environment. Variables that are not set are kept, like Windows does.
+func (a ExecAction) ExpandedPath() string {
+	return expandEnv(a.Path)
+}
+
+// expandEnv replaces the %NAME% environment variables in s
+func expandEnv(s string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, '%')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start+1:], '%')
+		if end < 0 {
+			break
+		}
+		end += start + 1
+		b.WriteString(s[:start])
+		if value, ok := os.LookupEnv(s[start+1 : end]); ok && end > start+1 {
+			b.WriteString(value)
+			s = s[end+1:]
+		} else {
+			// Keep the unknown variable, its closing % may start the next one
+			b.WriteString(s[start:end])
+			s = s[end:]
+		}
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// ComHandlerAction is an action defined in a scheduled Task if type IComHandlerAction.
+type ComHandlerAction struct {
+	ClassID string `json:"classId"` // CLSID of the COM handler, e.g. "{A6BA00FE-40E8-477C-B713-C64A14F19ADF}"
+	Data    string `json:"data"`    // passed to the COM handler
+}
+
+// Type returns ActionTypeComHandler
+func (ComHandlerAction) Type() ActionType { return ActionTypeComHandler }
+
+// EmailAction is an action defined in a scheduled Task if type IEmailAction. It is
+// deprecated since Windows 8 but may still be defined in legacy tasks.
+type EmailAction struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	Server  string `json:"server"` // SMTP server
+}
+
+// Type returns ActionTypeEmail
+func (EmailAction) Type() ActionType { return ActionTypeEmail }
+
+// ShowMessageAction is an action defined in a scheduled Task if type IShowMessageAction.
+// It is deprecated since Windows 8 but may still be defined in legacy tasks.
+type ShowMessageAction struct {
+	Title       string `json:"title"`
+	MessageBody string `json:"messageBody"`
+}
+
+// Type returns ActionTypeShowMessage
+func (ShowMessageAction) Type() ActionType { return ActionTypeShowMessage }
+
+// RawAction is an action defined in a scheduled Task of a type that is not supported by
+// this package. It contains the properties of the action that could be read, e.g. "id" and
+// "type", with their raw values.
+type RawAction map[string]interface{}
+
+// Type returns the TASK_ACTION_TYPE of the action
+func (a RawAction) Type() ActionType {
+	actionType, _ := a["type"].(int32)
+	return ActionType(actionType)
+}
+
+// rawActionProperties are the properties read for a RawAction, which are the properties of
+// all action types known so far.
+var rawActionProperties = []string{
+	"id", "type", "path", "arguments", "workingDirectory", "classId", "data",
+	"from", "to", "cc", "bcc", "replyTo", "subject", "body", "server", "title", "messageBody",
+}
+
+// parseActions reads the IActionCollection of a ITaskDefinition object. Actions of
+// unsupported types are returned as RawAction. The properties that can not be read are
+// recorded by w, e.g. "actions[1].path".
+func parseActions(definition dispatcher, w warnings) (actions []Action) {
+	collection, err := getObject(definition, "actions")
+	if err != nil {
+		w.warn("actions", err)
+		return
+	}
+	defer collection.Release()
+	count := getInt(collection, "count", w.nested("actions"))
+	for i := 1; i <= count; i++ {
+		// Get Action i
+		name := fmt.Sprintf("actions[%d]", i)
+		action, err := getObject(collection, "item", int32(i))
+		if err != nil {
+			w.warn(name, err)
+			continue
+		}
+		aw := w.nested(name)
+		value, err := action.GetProperty("type")
+		if err != nil {
+			aw.warn("type", err)
+			action.Release()
+			continue
+		}
+		switch ActionType(toInt(value)) {
+		case ActionTypeExec:
+			// Properties that are not set or can not be read are empty. The working directory
+			// and the arguments are optional, so they are read without warnings.
+			actions = append(actions, ExecAction{
+				WorkingDirectory: getString(action, "workingDirectory", nil),
+				Path:             getString(action, "path", aw),
+				Arguments:        getString(action, "arguments", nil),
+			})
+		case ActionTypeComHandler:
+			actions = append(actions, ComHandlerAction{
+				ClassID: getString(action, "classId", aw),
+				Data:    getString(action, "data", nil), // optional
+			})
+		case ActionTypeEmail:
+			actions = append(actions, EmailAction{
+				From:    getString(action, "from", aw),
+				To:      getString(action, "to", aw),
+				Subject: getString(action, "subject", aw),
+				Body:    getString(action, "body", aw),
+				Server:  getString(action, "server", aw),
+			})
+		case ActionTypeShowMessage:
+			actions = append(actions, ShowMessageAction{
+				Title:       getString(action, "title", aw),
+				MessageBody: getString(action, "messageBody", aw),
+			})
+		default:
+			actions = append(actions, parseRawAction(action))
+		}
+		action.Release()
+	}
+	return
+}
+
+// parseRawAction reads the properties of an action of an unsupported type. Properties that can
+// not be read are not recorded as warnings, since most of them do not exist for the type.
+func parseRawAction(action dispatcher) RawAction {
+	raw := RawAction{}
+	for _, name := range rawActionProperties {
+		value, err := action.GetProperty(name)
+		if err != nil {
+			continue
+		}
+		// Only plain values are kept, objects are released right away
+		if d, ok := value.(dispatcher); ok {
+			d.Release()
+			continue
+		}
+		if value != nil {
+			raw[name] = value
+		}
+	}
+	return raw
+}
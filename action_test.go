@@ -0,0 +1,79 @@
+package taskscheduler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseActionsMissingWorkingDirectory(t *testing.T) {
+	com := &fakeCOM{t: t}
+	// The working directory of the action is not set, so it can not be read
+	definition := com.acquire(com.object("definition", map[string]interface{}{
+		"actions": com.collection("actions", com.object("exec action", map[string]interface{}{
+			"type":      int32(ActionTypeExec),
+			"path":      "C:\\backup.exe",
+			"arguments": "--full",
+		})),
+	}))
+	var warned []string
+	actions := parseActions(definition, func(property string, err error) { warned = append(warned, property) })
+	definition.Release()
+	com.checkReleased()
+	want := []Action{ExecAction{Path: "C:\\backup.exe", Arguments: "--full"}}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("got actions %#v, want %#v", actions, want)
+	}
+	// The working directory is optional, so it must not be reported as unreadable
+	if len(warned) > 0 {
+		t.Errorf("got warnings for %v, want none", warned)
+	}
+}
+
+func TestActionJSON(t *testing.T) {
+	actions := Actions{
+		ExecAction{Path: "C:\\backup.exe"},
+		ComHandlerAction{ClassID: "{A6BA00FE-40E8-477C-B713-C64A14F19ADF}"},
+		RawAction{"type": int32(99), "id": "Raw"},
+	}
+	data, err := json.Marshal(actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"type":"Exec","workingDirectory":"","path":"C:\\backup.exe","arguments":""},` +
+		`{"type":"ComHandler","classId":"{A6BA00FE-40E8-477C-B713-C64A14F19ADF}","data":""},` +
+		`{"id":"Raw","type":99}]`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+	var decoded Actions
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, actions) {
+		t.Errorf("decoded %#v, want %#v", decoded, actions)
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("TASKSCHEDULER_ROOT", "C:\\Windows")
+	t.Setenv("TASKSCHEDULER_EMPTY", "")
+	tests := []struct {
+		s, want string
+	}{
+		{s: "%TASKSCHEDULER_ROOT%\\notepad.exe", want: "C:\\Windows\\notepad.exe"},
+		{s: "%TASKSCHEDULER_ROOT%%TASKSCHEDULER_ROOT%", want: "C:\\WindowsC:\\Windows"},
+		{s: "x%TASKSCHEDULER_EMPTY%y", want: "xy"},
+		// Unknown variables are kept and their closing % may start the next variable
+		{s: "%TASKSCHEDULER_UNSET%\\a.exe", want: "%TASKSCHEDULER_UNSET%\\a.exe"},
+		{s: "50%%TASKSCHEDULER_ROOT%", want: "50%C:\\Windows"},
+		{s: "100% done", want: "100% done"},
+		{s: "%%", want: "%%"},
+		{s: "", want: ""},
+	}
+	for _, test := range tests {
+		if got := expandEnv(test.s); got != test.want {
+			t.Errorf("expandEnv(%q) = %q, want %q", test.s, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package taskscheduler
+
+import "time"
+
+// NewTaskDefinition returns an empty TaskDefinition with the given name. Use the fluent
+// helpers to complete it, e.g.
+//
+//	def := NewTaskDefinition("Backup").
+//		AddExecAction("C:\\backup.exe", "--full", "").
+//		AddDailyTrigger(time.Now(), 1)
+//	task, err := RegisterTask("\\", def)
+func NewTaskDefinition(name string) TaskDefinition {
+	return TaskDefinition{Name: name}
+}
+
+// SetDescription returns a copy of d with the given description
+func (d TaskDefinition) SetDescription(description string) TaskDefinition {
+	d.Description = description
+	return d
+}
+
+// AddExecAction returns a copy of d with an additional ExecAction that runs path with args in workDir
+func (d TaskDefinition) AddExecAction(path, args, workDir string) TaskDefinition {
+	// Use a full slice expression, so copies of d never share the appended element
+	d.ActionList = append(d.ActionList[:len(d.ActionList):len(d.ActionList)], ExecAction{
+		WorkingDirectory: workDir,
+		Path:             path,
+		Arguments:        args,
+	})
+	return d
+}
+
+// AddTimeTrigger returns a copy of d with an additional TimeTrigger that starts the task once at at
+func (d TaskDefinition) AddTimeTrigger(at time.Time) TaskDefinition {
+	return d.addTrigger(TimeTrigger{
+		TaskTrigger: TaskTrigger{Enabled: true, StartBoundary: at},
+	})
+}
+
+// AddDailyTrigger returns a copy of d with an additional DailyTrigger that starts the task
+// at start and then every daysInterval days. RegisterTask fails if daysInterval is less than 1
+// or start is the zero time.
+func (d TaskDefinition) AddDailyTrigger(start time.Time, daysInterval int) TaskDefinition {
+	return d.addTrigger(DailyTrigger{
+		TaskTrigger:  TaskTrigger{Enabled: true, StartBoundary: start},
+		DaysInterval: daysInterval,
+	})
+}
+
+// AddWeeklyTrigger returns a copy of d with an additional WeeklyTrigger that starts the task
+// at the time of day of start on days, every weeksInterval weeks beginning with the week of start.
+// RegisterTask fails if weeksInterval is less than 1, no days are passed or start is the zero time.
+func (d TaskDefinition) AddWeeklyTrigger(start time.Time, weeksInterval int, days ...time.Weekday) TaskDefinition {
+	return d.addTrigger(WeeklyTrigger{
+		TaskTrigger:   TaskTrigger{Enabled: true, StartBoundary: start},
+		WeeksInterval: weeksInterval,
+		DaysOfWeek:    append([]time.Weekday(nil), days...),
+	})
+}
+
+// SetPrincipal returns a copy of d that runs in the security context of p
+func (d TaskDefinition) SetPrincipal(p Principal) TaskDefinition {
+	d.Principal = p
+	return d
+}
+
+// SetRestartPolicy returns a copy of d that restarts the task up to count times every interval
+// if it fails
+func (d TaskDefinition) SetRestartPolicy(count int, interval time.Duration) TaskDefinition {
+	d.RestartPolicy = RestartPolicy{Count: count, Interval: interval}
+	return d
+}
+
+func (d TaskDefinition) addTrigger(t Trigger) TaskDefinition {
+	d.TriggerList = append(d.TriggerList[:len(d.TriggerList):len(d.TriggerList)], t)
+	return d
+}
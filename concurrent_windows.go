@@ -0,0 +1,93 @@
+package taskscheduler
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/go-ole/go-ole"
+)
+
+// GetTasksConcurrent returns a list of all scheduled Tasks in Windows Task Scheduler 2.0 like
+// GetTasks, but walks the folders in parallel with up to limit goroutines. Each goroutine uses its
+// own connection to Task Scheduler 2.0. runtime.NumCPU() goroutines are used if limit is not positive.
+// Folders that can not be read are reported by a BatchError, the Tasks of the other folders are
+// still returned.
+func GetTasksConcurrent(limit int) ([]Task, error) {
+	if limit <= 0 {
+		limit = runtime.NumCPU()
+	}
+	var (
+		folders []string
+		failed  = BatchError{}
+		mu      sync.Mutex // guards failed while the goroutines walk the folders
+	)
+	err := withTaskService(func(ts *ole.IDispatch) (err error) {
+		folders, err = getFolders(ts, failed)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	if limit > len(folders) {
+		limit = len(folders)
+	}
+	var (
+		results = make([][]Task, len(folders)) // Tasks per folder to keep the order of the folders
+		indices = make(chan int)
+		errs    = make(chan error, limit)
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every goroutine locks its OS thread and initializes COM while connecting
+			errs <- withTaskService(func(ts *ole.IDispatch) error {
+				w := &walker{ctx: context.Background(), shallow: true, onError: func(path string, err error) {
+					mu.Lock()
+					failed.add(path, err)
+					mu.Unlock()
+				}}
+				for i := range indices {
+					tasks, err := w.getTasksInFolder(ts, folders[i])
+					if err != nil && err != ErrFolderNotFound {
+						return err
+					}
+					results[i] = tasks
+				}
+				return nil
+			})
+		}()
+	}
+	// Stop handing out folders if a goroutine failed, e.g. because it could not connect
+	var firstErr error
+	for i := range folders {
+		if firstErr != nil {
+			break
+		}
+		select {
+		case indices <- i:
+		case firstErr = <-errs:
+		}
+	}
+	close(indices)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	var tasks []Task
+	for _, t := range results {
+		tasks = append(tasks, t...)
+	}
+	if len(failed) > 0 {
+		return tasks, failed
+	}
+	return tasks, nil
+}
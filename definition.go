@@ -0,0 +1,81 @@
+package taskscheduler
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTaskExists is returned by RegisterTask if a task with the same name already
+// exists and TaskDefinition.Overwrite is not set.
+var ErrTaskExists = errors.New("Task already exists")
+
+// ErrLogonFailure is returned by RegisterTask if the user or password of the principal is rejected
+var ErrLogonFailure = errors.New("Logon failure: unknown user name or bad password")
+
+// TaskDefinition describes a task that can be registered with RegisterTask
+type TaskDefinition struct {
+	Name          string        `json:"name"` // Name of the task inside of its folder
+	Description   string        `json:"description"`
+	ActionList    []ExecAction  `json:"actionList"`
+	TriggerList   Triggers      `json:"triggerList"`
+	Principal     Principal     `json:"principal"`
+	RestartPolicy RestartPolicy `json:"restartPolicy"` // Restart the task if it fails, only set if Count is positive
+	Disabled      bool          `json:"disabled"`      // Register the task disabled, it is enabled by default
+	Overwrite     bool          `json:"overwrite"`     // Overwrite an existing task with the same name
+	Password      string        `json:"-"`             // Password of Principal.UserID, see RegisterTask
+}
+
+// RegisterTaskWithCredentials registers def like RegisterTask, but runs the Task as user with
+// logonType, e.g. LogonTypePassword with the password of user or LogonTypeS4U without password.
+// The password is only passed to the Task Scheduler and never logged or stored.
+func RegisterTaskWithCredentials(path string, def TaskDefinition, user, password string, logonType LogonType) (Task, error) {
+	def.Principal.UserID = user
+	def.Principal.LogonType = logonType
+	def.Password = password
+	return RegisterTask(path, def)
+}
+
+// credentials returns the user and password parameters of ITaskFolder::RegisterTaskDefinition
+// for the logon type of def, nil parameters use the principal of the definition.
+func credentials(def TaskDefinition) (user, password interface{}, err error) {
+	switch def.Principal.LogonType {
+	case LogonTypePassword, LogonTypeInteractiveTokenOrPassword:
+		if def.Principal.UserID == "" || def.Password == "" {
+			return nil, nil, errors.New("Task definition with password logon has no user or password")
+		}
+		return def.Principal.UserID, def.Password, nil
+	case LogonTypeS4U, LogonTypeServiceAccount:
+		if def.Principal.UserID == "" {
+			return nil, nil, errors.New("Task definition with S4U or service account logon has no user")
+		}
+		return def.Principal.UserID, nil, nil
+	}
+	return nil, nil, nil
+}
+
+// validateTriggers returns an error for triggers of a definition that can never start a
+// task, before they are passed to the Task Scheduler.
+func validateTriggers(triggers []Trigger) error {
+	for i, trigger := range triggers {
+		switch t := trigger.(type) {
+		case DailyTrigger:
+			if t.DaysInterval < 1 {
+				return fmt.Errorf("Daily trigger %d of task definition has days interval %d, must be at least 1", i+1, t.DaysInterval)
+			}
+			if t.StartBoundary.IsZero() {
+				return fmt.Errorf("Daily trigger %d of task definition has no start boundary", i+1)
+			}
+		case WeeklyTrigger:
+			if t.WeeksInterval < 1 {
+				return fmt.Errorf("Weekly trigger %d of task definition has weeks interval %d, must be at least 1", i+1, t.WeeksInterval)
+			}
+			if len(t.DaysOfWeek) == 0 {
+				return fmt.Errorf("Weekly trigger %d of task definition has no days of week", i+1)
+			}
+			if t.StartBoundary.IsZero() {
+				return fmt.Errorf("Weekly trigger %d of task definition has no start boundary", i+1)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package taskscheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateTriggers(t *testing.T) {
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		def   TaskDefinition
+		valid bool
+	}{
+		{name: "daily", def: NewTaskDefinition("Task").AddDailyTrigger(start, 1), valid: true},
+		{name: "daily without interval", def: NewTaskDefinition("Task").AddDailyTrigger(start, 0)},
+		{name: "daily without start", def: NewTaskDefinition("Task").AddDailyTrigger(time.Time{}, 1)},
+		{name: "weekly", def: NewTaskDefinition("Task").AddWeeklyTrigger(start, 2, time.Monday, time.Friday), valid: true},
+		{name: "weekly without interval", def: NewTaskDefinition("Task").AddWeeklyTrigger(start, 0, time.Monday)},
+		{name: "weekly with negative interval", def: NewTaskDefinition("Task").AddWeeklyTrigger(start, -1, time.Monday)},
+		{name: "weekly without days", def: NewTaskDefinition("Task").AddWeeklyTrigger(start, 1)},
+		{name: "weekly without start", def: NewTaskDefinition("Task").AddWeeklyTrigger(time.Time{}, 1, time.Monday)},
+		{name: "valid and invalid", def: NewTaskDefinition("Task").AddDailyTrigger(start, 1).AddWeeklyTrigger(start, 1)},
+	}
+	for _, test := range tests {
+		err := validateTriggers(test.def.TriggerList)
+		if test.valid && err != nil {
+			t.Errorf("%s: got error %v, want none", test.name, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("%s: got no error, want an error", test.name)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package taskscheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// TASK_CREATION flags of ITaskFolder::RegisterTaskDefinition
+const (
+	taskValidateOnly   = 0x1
+	taskCreate         = 0x2
+	taskUpdate         = 0x4
+	taskCreateOrUpdate = 0x6
+)
+
+// RegisterTask registers a new Task in the folder path, e.g. "\\MyApp", of Windows Task Scheduler 2.0
+//
+// The Principal.LogonType of def defines when the Task runs:
+//
+//   - LogonTypeInteractiveToken (default) only runs the Task while the user is logged on
+//   - LogonTypePassword runs the Task whether the user is logged on or not. The password of
+//     Principal.UserID must be set in def.Password, Windows stores it to log on the user.
+//   - LogonTypeS4U runs the Task whether the user is logged on or not without storing a
+//     password, but the Task can not access network resources. It requires administrative
+//     privileges to register.
+//   - LogonTypeServiceAccount runs the Task as Principal.UserID, e.g. "SYSTEM", without password
+//
+// The password is only passed to the Task Scheduler for the registration and never stored by this package.
+func RegisterTask(path string, def TaskDefinition) (task Task, err error) {
+	return registerTask(path, def, 0)
+}
+
+// ValidateDefinition checks def like RegisterTask would without registering the Task, so errors
+// of the schema or the credentials are reported. Existing tasks with the same name are ignored.
+func ValidateDefinition(def TaskDefinition) error {
+	def.Overwrite = true
+	_, err := registerTask("\\", def, taskValidateOnly)
+	return err
+}
+
+// registerTask registers def in the folder path with the additional TASK_CREATION flags
+func registerTask(path string, def TaskDefinition, extraFlags int) (task Task, err error) {
+	if def.Name == "" {
+		return task, errors.New("Task definition has no name")
+	}
+	if err = validateTriggers(def.TriggerList); err != nil {
+		return task, err
+	}
+	user, password, err := credentials(def)
+	if err != nil {
+		return task, err
+	}
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		// Get the folder the task is registered in
+		folder, err := getFolder(ts, path)
+		if err == ErrFolderNotFound {
+			return fmt.Errorf("%w: %s", ErrFolderNotFound, path)
+		} else if err != nil {
+			return err
+		}
+		defer folder.Release()
+		// Create a new ITaskDefinition and fill it with the given definition
+		variant, err := oleutil.CallMethod(ts, "NewTask", int64(0))
+		if err != nil {
+			return &COMError{Op: "create task definition in Task Scheduler 2.0", Err: err}
+		}
+		definition := variant.ToIDispatch()
+		defer definition.Release()
+		if err := putDefinition(definition, def); err != nil {
+			return err
+		}
+		// Register the definition, the credentials of the principal are used
+		flags := taskCreate
+		if def.Overwrite {
+			flags = taskCreateOrUpdate
+		}
+		flags |= extraFlags
+		logonType := def.Principal.LogonType
+		if logonType == LogonTypeNone {
+			logonType = LogonTypeInteractiveToken
+		}
+		variant, err = oleutil.CallMethod(folder, "RegisterTaskDefinition", def.Name, definition, int32(flags), user, password, int32(logonType), nil)
+		if err != nil {
+			switch hresult(err) {
+			case hresultAlreadyExists:
+				return ErrTaskExists
+			case hresultLogonFailure:
+				return ErrLogonFailure
+			}
+			return &COMError{Op: "register task in Task Scheduler 2.0", Err: err}
+		}
+		// Nothing is registered if the definition is only validated
+		registered := variant.ToIDispatch()
+		if registered == nil {
+			return nil
+		}
+		defer registered.Release()
+		task = parseTask(comObject{disp: registered})
+		return nil
+	})
+	return
+}
+
+// UpdateTask changes the definition of the existing Task at path without deleting it, so its
+// history is preserved. The following fields of def are updated if they are set:
+//
+//   - Description replaces the description
+//   - ActionList replaces all actions, an empty non-nil list removes them
+//   - TriggerList replaces all triggers, an empty non-nil list removes them
+//   - Principal replaces the principal if it is not the zero value
+//   - Disabled disables the task if it is set
+//   - RestartPolicy replaces the restart policy if its count is positive
+//
+// Name and Overwrite are ignored. Tasks with LogonTypePassword can not be updated this
+// way because the password has to be passed again.
+func UpdateTask(path string, def TaskDefinition) error {
+	if err := validateTriggers(def.TriggerList); err != nil {
+		return err
+	}
+	folderPath, name := splitPath(path)
+	return withTaskService(func(ts *ole.IDispatch) error {
+		folder, err := getFolder(ts, folderPath)
+		if err == ErrFolderNotFound {
+			return ErrTaskNotFound
+		} else if err != nil {
+			return err
+		}
+		defer folder.Release()
+		registered, err := getTaskInFolder(folder, name)
+		if err != nil {
+			return err
+		}
+		defer registered.Release()
+		// Apply the changes to the existing definition
+		variant, err := oleutil.GetProperty(registered, "definition")
+		if err != nil {
+			return &COMError{Op: "get definition of task in Task Scheduler 2.0", Err: err}
+		}
+		definition := variant.ToIDispatch()
+		defer definition.Release()
+		if err := putDefinition(definition, def); err != nil {
+			return err
+		}
+		// Register the definition again, keeping the logon type of its principal
+		logonType := parsePrincipal(comObject{disp: definition}, nil).LogonType
+		if logonType == LogonTypeNone {
+			logonType = LogonTypeInteractiveToken
+		}
+		if variant, err = oleutil.CallMethod(folder, "RegisterTaskDefinition", name, definition, int32(taskUpdate), nil, nil, int32(logonType), nil); err != nil {
+			return &COMError{Op: "update task in Task Scheduler 2.0", Err: err}
+		}
+		if updated := variant.ToIDispatch(); updated != nil {
+			updated.Release()
+		}
+		return nil
+	})
+}
+
+// putDefinition writes def into a ITaskDefinition object. Only the fields that are set in
+// def are written, lists that are set replace the existing actions or triggers.
+func putDefinition(definition *ole.IDispatch, def TaskDefinition) error {
+	// Set registration info
+	if def.Description != "" {
+		variant, err := oleutil.GetProperty(definition, "registrationInfo")
+		if err != nil {
+			return &COMError{Op: "get registration info of task definition", Err: err}
+		}
+		registrationInfo := variant.ToIDispatch()
+		defer registrationInfo.Release()
+		if _, err := oleutil.PutProperty(registrationInfo, "description", def.Description); err != nil {
+			return &COMError{Op: "set description of task definition", Err: err}
+		}
+	}
+	// Set settings
+	if def.Disabled || def.RestartPolicy.Count > 0 {
+		variant, err := oleutil.GetProperty(definition, "settings")
+		if err != nil {
+			return &COMError{Op: "get settings of task definition", Err: err}
+		}
+		settings := variant.ToIDispatch()
+		defer settings.Release()
+		if def.Disabled {
+			if _, err := oleutil.PutProperty(settings, "enabled", false); err != nil {
+				return &COMError{Op: "set enabled of task definition", Err: err}
+			}
+		}
+		if def.RestartPolicy.Count > 0 {
+			if _, err := oleutil.PutProperty(settings, "restartCount", int32(def.RestartPolicy.Count)); err != nil {
+				return &COMError{Op: "set restart count of task definition", Err: err}
+			}
+			if _, err := oleutil.PutProperty(settings, "restartInterval", formatDuration(def.RestartPolicy.Interval)); err != nil {
+				return &COMError{Op: "set restart interval of task definition", Err: err}
+			}
+		}
+	}
+	// Replace actions
+	if def.ActionList != nil {
+		variant, err := oleutil.GetProperty(definition, "actions")
+		if err != nil {
+			return &COMError{Op: "get actions of task definition", Err: err}
+		}
+		actions := variant.ToIDispatch()
+		defer actions.Release()
+		if _, err := oleutil.CallMethod(actions, "Clear"); err != nil {
+			return &COMError{Op: "clear actions of task definition", Err: err}
+		}
+		for _, a := range def.ActionList {
+			if err := putExecAction(actions, a); err != nil {
+				return err
+			}
+		}
+	}
+	// Replace triggers
+	if def.TriggerList != nil {
+		variant, err := oleutil.GetProperty(definition, "triggers")
+		if err != nil {
+			return &COMError{Op: "get triggers of task definition", Err: err}
+		}
+		triggers := variant.ToIDispatch()
+		defer triggers.Release()
+		if _, err := oleutil.CallMethod(triggers, "Clear"); err != nil {
+			return &COMError{Op: "clear triggers of task definition", Err: err}
+		}
+		for _, t := range def.TriggerList {
+			if err := putTrigger(triggers, t); err != nil {
+				return err
+			}
+		}
+	}
+	// Set principal
+	if def.Principal != (Principal{}) {
+		variant, err := oleutil.GetProperty(definition, "principal")
+		if err != nil {
+			return &COMError{Op: "get principal of task definition", Err: err}
+		}
+		principal := variant.ToIDispatch()
+		defer principal.Release()
+		return putPrincipal(principal, def.Principal)
+	}
+	return nil
+}
+
+func putExecAction(actions *ole.IDispatch, a ExecAction) error {
+	variant, err := oleutil.CallMethod(actions, "Create", int32(0)) // TASK_ACTION_EXEC
+	if err != nil {
+		return &COMError{Op: "create exec action", Err: err}
+	}
+	action := variant.ToIDispatch()
+	defer action.Release()
+	if _, err := oleutil.PutProperty(action, "path", a.Path); err != nil {
+		return &COMError{Op: "set path of exec action", Err: err}
+	}
+	if _, err := oleutil.PutProperty(action, "arguments", a.Arguments); err != nil {
+		return &COMError{Op: "set arguments of exec action", Err: err}
+	}
+	if _, err := oleutil.PutProperty(action, "workingDirectory", a.WorkingDirectory); err != nil {
+		return &COMError{Op: "set working directory of exec action", Err: err}
+	}
+	return nil
+}
+
+func putTrigger(triggers *ole.IDispatch, t Trigger) error {
+	variant, err := oleutil.CallMethod(triggers, "Create", int32(t.Type()))
+	if err != nil {
+		return &COMError{Op: fmt.Sprintf("create trigger of type %d", t.Type()), Err: err}
+	}
+	trigger := variant.ToIDispatch()
+	defer trigger.Release()
+	// Set common fields
+	common := t.Common()
+	if _, err := oleutil.PutProperty(trigger, "enabled", common.Enabled); err != nil {
+		return &COMError{Op: "set enabled of trigger", Err: err}
+	}
+	if !common.StartBoundary.IsZero() {
+		if _, err := oleutil.PutProperty(trigger, "startBoundary", common.StartBoundary.Format(time.RFC3339)); err != nil {
+			return &COMError{Op: "set start boundary of trigger", Err: err}
+		}
+	}
+	if !common.EndBoundary.IsZero() {
+		if _, err := oleutil.PutProperty(trigger, "endBoundary", common.EndBoundary.Format(time.RFC3339)); err != nil {
+			return &COMError{Op: "set end boundary of trigger", Err: err}
+		}
+	}
+	if common.ExecutionTimeLimit > 0 {
+		if _, err := oleutil.PutProperty(trigger, "executionTimeLimit", formatDuration(common.ExecutionTimeLimit)); err != nil {
+			return &COMError{Op: "set execution time limit of trigger", Err: err}
+		}
+	}
+	if common.Repetition.Interval > 0 {
+		if err := putRepetition(trigger, common.Repetition); err != nil {
+			return err
+		}
+	}
+	// Set type specific fields
+	switch t := t.(type) {
+	case EventTrigger:
+		if _, err := oleutil.PutProperty(trigger, "subscription", t.Subscription); err != nil {
+			return &COMError{Op: "set subscription of trigger", Err: err}
+		}
+		if t.Delay > 0 {
+			if _, err := oleutil.PutProperty(trigger, "delay", formatDuration(t.Delay)); err != nil {
+				return &COMError{Op: "set delay of trigger", Err: err}
+			}
+		}
+		if len(t.ValueQueries) > 0 {
+			variant, err := oleutil.GetProperty(trigger, "valueQueries")
+			if err != nil {
+				return &COMError{Op: "get value queries of trigger", Err: err}
+			}
+			valueQueries := variant.ToIDispatch()
+			defer valueQueries.Release()
+			for name, query := range t.ValueQueries {
+				if variant, err = oleutil.CallMethod(valueQueries, "Create", name, query); err != nil {
+					return &COMError{Op: "create value query of trigger", Err: err}
+				}
+				if pair := variant.ToIDispatch(); pair != nil {
+					pair.Release()
+				}
+			}
+		}
+	case DailyTrigger:
+		if _, err := oleutil.PutProperty(trigger, "daysInterval", int16(t.DaysInterval)); err != nil {
+			return &COMError{Op: "set days interval of trigger", Err: err}
+		}
+	case WeeklyTrigger:
+		if _, err := oleutil.PutProperty(trigger, "weeksInterval", int16(t.WeeksInterval)); err != nil {
+			return &COMError{Op: "set weeks interval of trigger", Err: err}
+		}
+		if _, err := oleutil.PutProperty(trigger, "daysOfWeek", int16(encodeDaysOfWeek(t.DaysOfWeek))); err != nil {
+			return &COMError{Op: "set days of week of trigger", Err: err}
+		}
+	case MonthlyTrigger:
+		if _, err := oleutil.PutProperty(trigger, "daysOfMonth", int32(encodeDaysOfMonth(t.DaysOfMonth))); err != nil {
+			return &COMError{Op: "set days of month of trigger", Err: err}
+		}
+		if _, err := oleutil.PutProperty(trigger, "monthsOfYear", int16(encodeMonthsOfYear(t.MonthsOfYear))); err != nil {
+			return &COMError{Op: "set months of year of trigger", Err: err}
+		}
+		if _, err := oleutil.PutProperty(trigger, "runOnLastDayOfMonth", t.RunOnLastDayOfMonth); err != nil {
+			return &COMError{Op: "set run on last day of month of trigger", Err: err}
+		}
+	case MonthlyDOWTrigger:
+		// The last week is set with RunOnLastWeekOfMonth, not in the WeeksOfMonth bitmask
+		weeks := encodeWeeksOfMonth(t.WeeksOfMonth)
+		if _, err := oleutil.PutProperty(trigger, "weeksOfMonth", int16(weeks&^lastWeekOfMonthBit)); err != nil {
+			return &COMError{Op: "set weeks of month of trigger", Err: err}
+		}
+		if _, err := oleutil.PutProperty(trigger, "runOnLastWeekOfMonth", weeks&lastWeekOfMonthBit != 0); err != nil {
+			return &COMError{Op: "set run on last week of month of trigger", Err: err}
+		}
+		if _, err := oleutil.PutProperty(trigger, "daysOfWeek", int16(encodeDaysOfWeek(t.DaysOfWeek))); err != nil {
+			return &COMError{Op: "set days of week of trigger", Err: err}
+		}
+		if _, err := oleutil.PutProperty(trigger, "monthsOfYear", int16(encodeMonthsOfYear(t.MonthsOfYear))); err != nil {
+			return &COMError{Op: "set months of year of trigger", Err: err}
+		}
+	case RegistrationTrigger:
+		if t.Delay > 0 {
+			if _, err := oleutil.PutProperty(trigger, "delay", formatDuration(t.Delay)); err != nil {
+				return &COMError{Op: "set delay of trigger", Err: err}
+			}
+		}
+	case SessionStateChangeTrigger:
+		if _, err := oleutil.PutProperty(trigger, "stateChange", int32(t.StateChange)); err != nil {
+			return &COMError{Op: "set state change of trigger", Err: err}
+		}
+		if t.UserID != "" {
+			if _, err := oleutil.PutProperty(trigger, "userId", t.UserID); err != nil {
+				return &COMError{Op: "set user id of trigger", Err: err}
+			}
+		}
+		if t.Delay > 0 {
+			if _, err := oleutil.PutProperty(trigger, "delay", formatDuration(t.Delay)); err != nil {
+				return &COMError{Op: "set delay of trigger", Err: err}
+			}
+		}
+	case BootTrigger:
+		if t.Delay > 0 {
+			if _, err := oleutil.PutProperty(trigger, "delay", formatDuration(t.Delay)); err != nil {
+				return &COMError{Op: "set delay of trigger", Err: err}
+			}
+		}
+	case LogonTrigger:
+		if t.UserID != "" {
+			if _, err := oleutil.PutProperty(trigger, "userId", t.UserID); err != nil {
+				return &COMError{Op: "set user id of trigger", Err: err}
+			}
+		}
+		if t.Delay > 0 {
+			if _, err := oleutil.PutProperty(trigger, "delay", formatDuration(t.Delay)); err != nil {
+				return &COMError{Op: "set delay of trigger", Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+func putRepetition(trigger *ole.IDispatch, r Repetition) error {
+	variant, err := oleutil.GetProperty(trigger, "repetition")
+	if err != nil {
+		return &COMError{Op: "get repetition of trigger", Err: err}
+	}
+	repetition := variant.ToIDispatch()
+	defer repetition.Release()
+	if _, err := oleutil.PutProperty(repetition, "interval", formatDuration(r.Interval)); err != nil {
+		return &COMError{Op: "set interval of repetition", Err: err}
+	}
+	if r.Duration > 0 {
+		if _, err := oleutil.PutProperty(repetition, "duration", formatDuration(r.Duration)); err != nil {
+			return &COMError{Op: "set duration of repetition", Err: err}
+		}
+	}
+	if _, err := oleutil.PutProperty(repetition, "stopAtDurationEnd", r.StopAtDurationEnd); err != nil {
+		return &COMError{Op: "set stop at duration end of repetition", Err: err}
+	}
+	return nil
+}
+
+func putPrincipal(principal *ole.IDispatch, p Principal) error {
+	if p.UserID != "" {
+		if _, err := oleutil.PutProperty(principal, "userId", p.UserID); err != nil {
+			return &COMError{Op: "set user id of principal", Err: err}
+		}
+	}
+	if p.GroupID != "" {
+		if _, err := oleutil.PutProperty(principal, "groupId", p.GroupID); err != nil {
+			return &COMError{Op: "set group id of principal", Err: err}
+		}
+	}
+	if p.LogonType != LogonTypeNone {
+		if _, err := oleutil.PutProperty(principal, "logonType", int32(p.LogonType)); err != nil {
+			return &COMError{Op: "set logon type of principal", Err: err}
+		}
+	}
+	if _, err := oleutil.PutProperty(principal, "runLevel", int32(p.RunLevel)); err != nil {
+		return &COMError{Op: "set run level of principal", Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package taskscheduler
+
+import (
+	"reflect"
+	"sort"
+)
+
+// TaskDiff is the difference between two enumerations of Tasks, see DiffTasks
+type TaskDiff struct {
+	Added    []string     `json:"added"`    // paths of the new Tasks
+	Removed  []string     `json:"removed"`  // paths of the removed Tasks
+	Modified []TaskChange `json:"modified"` // Tasks whose configuration changed
+}
+
+// TaskChange lists the fields of a Task that changed, e.g. "TriggerList" or "Settings"
+type TaskChange struct {
+	Path   string   `json:"path"`
+	Fields []string `json:"fields"`
+}
+
+// ignoredFields are the fields of a Task that change while it runs or are derived from
+// other fields, they are ignored by DiffTasks
+var ignoredFields = map[string]bool{
+	"ID":                 true,
+	"State":              true,
+	"LastRunTime":        true,
+	"NextRunTime":        true,
+	"LastTaskResult":     true,
+	"NumberOfMissedRuns": true,
+	"RunningInstances":   true,
+	"ParseWarnings":      true,
+}
+
+// DiffTasks returns the Tasks that were added, removed or modified from old to new, sorted by
+// path. Only the configuration of the Tasks is compared, fields that change while they run,
+// e.g. State or LastRunTime, are ignored.
+func DiffTasks(old, new []Task) (diff TaskDiff) {
+	previous, current := tasksByPath(old), tasksByPath(new)
+	for path, t := range current {
+		before, ok := previous[path]
+		if !ok {
+			diff.Added = append(diff.Added, path)
+			continue
+		}
+		if fields := changedFields(before, t); len(fields) > 0 {
+			diff.Modified = append(diff.Modified, TaskChange{Path: path, Fields: fields})
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			diff.Removed = append(diff.Removed, path)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Modified, func(i, j int) bool { return diff.Modified[i].Path < diff.Modified[j].Path })
+	return
+}
+
+// changedFields returns the names of the exported fields that differ between a and b
+func changedFields(a, b Task) (fields []string) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := 0; i < va.NumField(); i++ {
+		field := va.Type().Field(i)
+		if field.PkgPath != "" || ignoredFields[field.Name] {
+			continue
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			fields = append(fields, field.Name)
+		}
+	}
+	return
+}
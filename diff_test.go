@@ -0,0 +1,34 @@
+package taskscheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffTasks(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	old := []Task{
+		{Path: "\\Kept", Enabled: true, State: TaskStateReady},
+		{Path: "\\Removed"},
+		{Path: "\\Modified", Enabled: true, AllActions: Actions{ExecAction{Path: "a.exe"}}},
+	}
+	new := []Task{
+		// Fields that change while a task runs are ignored
+		{Path: "\\Kept", Enabled: true, State: TaskStateRunning, LastRunTime: now, ID: "changed"},
+		{Path: "\\Modified", AllActions: Actions{ExecAction{Path: "b.exe"}}},
+		{Path: "\\B"},
+		{Path: "\\A"},
+	}
+	want := TaskDiff{
+		Added:    []string{"\\A", "\\B"},
+		Removed:  []string{"\\Removed"},
+		Modified: []TaskChange{{Path: "\\Modified", Fields: []string{"Enabled", "AllActions"}}},
+	}
+	if diff := DiffTasks(old, new); !reflect.DeepEqual(diff, want) {
+		t.Errorf("got diff %+v, want %+v", diff, want)
+	}
+	if diff := DiffTasks(old, old); !reflect.DeepEqual(diff, TaskDiff{}) {
+		t.Errorf("got diff %+v of equal tasks, want an empty diff", diff)
+	}
+}
@@ -0,0 +1,84 @@
+package taskscheduler
+
+import "fmt"
+
+// dispatcher is a COM object as used by the walk over the folders of Task Scheduler 2.0 and
+// the parsing of its tasks. It is implemented by comObject on Windows, a fake tree of folders
+// allows to test the walk on any OS.
+type dispatcher interface {
+	// CallMethod calls the method name, objects are returned as dispatcher
+	CallMethod(name string, params ...interface{}) (interface{}, error)
+	// GetProperty returns the property name, objects are returned as dispatcher
+	GetProperty(name string, params ...interface{}) (interface{}, error)
+	Release()
+}
+
+// callObject calls the method name of d that returns an object
+func callObject(d dispatcher, name string, params ...interface{}) (dispatcher, error) {
+	value, err := d.CallMethod(name, params...)
+	if err != nil {
+		return nil, err
+	}
+	return asDispatcher(value, name)
+}
+
+// getObject returns the property name of d that is an object
+func getObject(d dispatcher, name string, params ...interface{}) (dispatcher, error) {
+	value, err := d.GetProperty(name, params...)
+	if err != nil {
+		return nil, err
+	}
+	return asDispatcher(value, name)
+}
+
+func asDispatcher(value interface{}, name string) (dispatcher, error) {
+	if d, ok := value.(dispatcher); ok {
+		return d, nil
+	}
+	return nil, fmt.Errorf("COM method or property %s did not return an object", name)
+}
+
+// warnings records that a property of a Task could not be read, like Task.warn. Nothing is
+// recorded by nil warnings, e.g. for objects that are not part of a Task.
+type warnings func(property string, err error)
+
+// warn records that property could not be read
+func (w warnings) warn(property string, err error) {
+	if w != nil {
+		w(property, err)
+	}
+}
+
+// nested returns warnings that record the properties of a nested object as "object.property"
+func (w warnings) nested(object string) warnings {
+	if w == nil {
+		return nil
+	}
+	return func(property string, err error) { w(object+"."+property, err) }
+}
+
+// getValue returns the property name of d, nil if it can not be read. The failure is recorded by w.
+func getValue(d dispatcher, name string, w warnings) interface{} {
+	value, err := d.GetProperty(name)
+	if err != nil {
+		w.warn(name, err)
+	}
+	return value
+}
+
+// getString returns the string property name of d, "" if it can not be read.
+func getString(d dispatcher, name string, w warnings) string {
+	s, _ := getValue(d, name, w).(string)
+	return s
+}
+
+// getBool returns the boolean property name of d, false if it can not be read.
+func getBool(d dispatcher, name string, w warnings) bool {
+	b, _ := getValue(d, name, w).(bool)
+	return b
+}
+
+// getInt returns the integer property name of d, 0 if it can not be read.
+func getInt(d dispatcher, name string, w warnings) int {
+	return toInt(getValue(d, name, w))
+}
@@ -0,0 +1,154 @@
+package taskscheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeCOM creates fake COM objects and counts their references like AddRef and Release
+type fakeCOM struct {
+	t    *testing.T
+	refs int // references that were acquired but not released yet
+}
+
+// fakeObject is a fake COM object, its members are the properties and methods by name:
+//
+//   - error is returned as failure of the call
+//   - func(params ...interface{}) interface{} is called and its result is returned
+//   - *fakeObject gets another reference that must be released
+//   - other values are returned as is
+//
+// Calls of missing members fail.
+type fakeObject struct {
+	com     *fakeCOM
+	name    string // describes the object in failures of the test
+	members map[string]interface{}
+	refs    int
+}
+
+func (c *fakeCOM) object(name string, members map[string]interface{}) *fakeObject {
+	return &fakeObject{com: c, name: name, members: members}
+}
+
+// acquire returns o with a new reference, e.g. for the root folder of a walk
+func (c *fakeCOM) acquire(o *fakeObject) *fakeObject {
+	o.refs++
+	c.refs++
+	return o
+}
+
+// collection returns a fake collection whose items are indexed from 1 like COM collections
+func (c *fakeCOM) collection(name string, items ...interface{}) *fakeObject {
+	return c.object(name, map[string]interface{}{
+		"count": int32(len(items)),
+		"item": func(params ...interface{}) interface{} {
+			i, ok := params[0].(int32)
+			if !ok || i < 1 || int(i) > len(items) {
+				return fmt.Errorf("%s has no item %v", name, params[0])
+			}
+			return items[i-1]
+		},
+	})
+}
+
+func (o *fakeObject) call(name string, params ...interface{}) (interface{}, error) {
+	member, ok := o.members[name]
+	if !ok {
+		return nil, fmt.Errorf("%s has no member %s", o.name, name)
+	}
+	if fn, ok := member.(func(params ...interface{}) interface{}); ok {
+		member = fn(params...)
+	}
+	switch member := member.(type) {
+	case error:
+		return nil, member
+	case *fakeObject:
+		return o.com.acquire(member), nil
+	}
+	return member, nil
+}
+
+func (o *fakeObject) CallMethod(name string, params ...interface{}) (interface{}, error) {
+	return o.call(name, params...)
+}
+
+func (o *fakeObject) GetProperty(name string, params ...interface{}) (interface{}, error) {
+	return o.call(name, params...)
+}
+
+func (o *fakeObject) Release() {
+	if o.refs <= 0 {
+		o.com.t.Errorf("%s released more often than acquired", o.name)
+		return
+	}
+	o.refs--
+	o.com.refs--
+}
+
+// checkReleased fails the test if references of objects were not released
+func (c *fakeCOM) checkReleased() {
+	c.t.Helper()
+	if c.refs != 0 {
+		c.t.Errorf("%d references of COM objects were not released", c.refs)
+	}
+}
+
+// fakeFolder describes a folder of a fake Task Scheduler
+type fakeFolder struct {
+	path    string
+	folders []fakeFolder
+	tasks   []string // names of the tasks
+	hidden  []string // names of the hidden tasks
+}
+
+// folder returns a fake ITaskFolder object of f and its subfolders
+func (c *fakeCOM) folder(f fakeFolder) *fakeObject {
+	var folders, tasks, hidden []interface{}
+	for _, sub := range f.folders {
+		folders = append(folders, c.folder(sub))
+	}
+	for _, name := range f.tasks {
+		tasks = append(tasks, c.task(joinPath(f.path, name)))
+	}
+	for _, name := range f.hidden {
+		hidden = append(hidden, c.task(joinPath(f.path, name)))
+	}
+	return c.object("folder "+f.path, map[string]interface{}{
+		"path":       f.path,
+		"GetFolders": c.collection("subfolders of "+f.path, folders...),
+		"GetTasks": func(params ...interface{}) interface{} {
+			if flags, _ := params[0].(int32); flags&taskEnumHidden != 0 {
+				return c.collection("tasks of "+f.path, append(tasks, hidden...)...)
+			}
+			return c.collection("tasks of "+f.path, tasks...)
+		},
+	})
+}
+
+// task returns a fake IRegisteredTask object at path with an empty definition
+func (c *fakeCOM) task(path string) *fakeObject {
+	_, name := splitPath(path)
+	return c.object("task "+path, map[string]interface{}{
+		"name":               name,
+		"path":               path,
+		"enabled":            true,
+		"state":              int32(TaskStateReady),
+		"lastRunTime":        nil,
+		"nextRunTime":        nil,
+		"lastTaskResult":     int32(0),
+		"numberOfMissedRuns": int32(0),
+		"GetInstances":       c.collection("instances of " + path),
+		"definition":         c.object("definition of "+path, map[string]interface{}{"xmlText": "<Task/>"}),
+	})
+}
+
+func joinPath(folder, name string) string {
+	if folder == "\\" {
+		return folder + name
+	}
+	return folder + "\\" + name
+}
+
+// errFake is returned by fake COM objects that are set up to fail
+var errFake = errors.New("fake COM failure")
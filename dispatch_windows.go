@@ -0,0 +1,44 @@
+package taskscheduler
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// comObject is the dispatcher of an IDispatch object
+type comObject struct {
+	disp *ole.IDispatch
+}
+
+func (o comObject) CallMethod(name string, params ...interface{}) (interface{}, error) {
+	return fromVariant(oleutil.CallMethod(o.disp, name, params...))
+}
+
+func (o comObject) GetProperty(name string, params ...interface{}) (interface{}, error) {
+	return fromVariant(oleutil.GetProperty(o.disp, name, params...))
+}
+
+func (o comObject) Release() {
+	o.disp.Release()
+}
+
+// fromVariant returns the value of variant, IDispatch objects are returned as comObject
+func fromVariant(variant *ole.VARIANT, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	if disp := variant.ToIDispatch(); disp != nil {
+		return comObject{disp: disp}, nil
+	}
+	value := variant.Value()
+	variant.Clear()
+	return value, nil
+}
+
+// toIDispatch returns the IDispatch object of d or nil if d is not a comObject
+func toIDispatch(d dispatcher) *ole.IDispatch {
+	if o, ok := d.(comObject); ok {
+		return o.disp
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package taskscheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// getDuration returns the ISO 8601 duration property name of d, 0 if it is not set, can not be
+// read or is malformed. Malformed durations are reported to w.
+func getDuration(d dispatcher, name string, w warnings) time.Duration {
+	duration, err := parseDuration(getString(d, name, w))
+	if err != nil {
+		w.warn(name, err)
+	}
+	return duration
+}
+
+// parseDuration parses an ISO 8601 duration of a task definition, e.g. "PT1H" or "P1DT12H".
+// Years and months are approximated with 365 and 30 days. 0 is returned if s is empty, an error
+// if s is malformed, e.g. "PT1H30" or "-PT1H".
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	malformed := fmt.Errorf("Malformed duration %q", s)
+	if len(s) < 2 || s[0] != 'P' || strings.HasSuffix(s, "T") {
+		return 0, malformed
+	}
+	var (
+		d      time.Duration
+		number string
+		inTime bool
+	)
+	for _, c := range s[1:] {
+		switch {
+		case c >= '0' && c <= '9' || c == '.' || c == ',':
+			if c == ',' {
+				c = '.'
+			}
+			number += string(c)
+			continue
+		case c == 'T':
+			if inTime || number != "" {
+				return 0, malformed
+			}
+			inTime = true
+			continue
+		}
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, malformed
+		}
+		number = ""
+		var unit time.Duration
+		switch {
+		case c == 'Y' && !inTime:
+			unit = 365 * 24 * time.Hour
+		case c == 'M' && !inTime:
+			unit = 30 * 24 * time.Hour
+		case c == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case c == 'H' && inTime:
+			unit = time.Hour
+		case c == 'M' && inTime:
+			unit = time.Minute
+		case c == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, malformed
+		}
+		d += time.Duration(value * float64(unit))
+	}
+	if number != "" {
+		return 0, malformed
+	}
+	return d, nil
+}
+
+// formatDuration formats d as an ISO 8601 duration, e.g. "PT1H30M". Fractions of seconds
+// are truncated, "PT0S" is returned for durations below one second.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("P")
+	if days := d / (24 * time.Hour); days > 0 {
+		b.WriteString(strconv.FormatInt(int64(days), 10) + "D")
+		d -= days * 24 * time.Hour
+	}
+	if d > 0 {
+		b.WriteString("T")
+		for _, unit := range []struct {
+			duration time.Duration
+			symbol   string
+		}{{time.Hour, "H"}, {time.Minute, "M"}, {time.Second, "S"}} {
+			if n := d / unit.duration; n > 0 {
+				b.WriteString(strconv.FormatInt(int64(n), 10) + unit.symbol)
+				d -= n * unit.duration
+			}
+		}
+	}
+	return b.String()
+}
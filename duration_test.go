@@ -0,0 +1,83 @@
+package taskscheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Duration
+	}{
+		{s: "", want: 0},
+		{s: "PT0S", want: 0},
+		{s: "PT1H", want: time.Hour},
+		{s: "PT1H30M", want: 90 * time.Minute},
+		{s: "P1DT12H", want: 36 * time.Hour},
+		{s: "P1W", want: 7 * 24 * time.Hour},
+		{s: "P1M", want: 30 * 24 * time.Hour},
+		{s: "P1Y", want: 365 * 24 * time.Hour},
+		{s: "PT0.5S", want: 500 * time.Millisecond},
+		{s: "PT1,5M", want: 90 * time.Second},
+	}
+	for _, test := range tests {
+		got, err := parseDuration(test.s)
+		if err != nil || got != test.want {
+			t.Errorf("parseDuration(%q) = %v, %v, want %v", test.s, got, err, test.want)
+		}
+	}
+	// Malformed durations are rejected instead of being parsed to 0
+	for _, s := range []string{"PT1H30", "-PT1H", "1H", "P", "PT", "P1DT", "P1H", "PT1D", "PTT1H", "P1T1H", "PT.H", "PT1X"} {
+		if got, err := parseDuration(s); err == nil {
+			t.Errorf("parseDuration(%q) = %v, want an error", s, got)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 0, want: "PT0S"},
+		{d: 500 * time.Millisecond, want: "PT0S"},
+		{d: 90 * time.Second, want: "PT1M30S"},
+		{d: time.Hour + 1500*time.Millisecond, want: "PT1H1S"},
+		{d: 24 * time.Hour, want: "P1D"},
+		{d: 36*time.Hour + 5*time.Minute, want: "P1DT12H5M"},
+	}
+	for _, test := range tests {
+		got := formatDuration(test.d)
+		if got != test.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", test.d, got, test.want)
+		}
+		// Durations of whole seconds are parsed back
+		if test.d%time.Second == 0 {
+			if back, err := parseDuration(got); err != nil || back != test.d {
+				t.Errorf("parseDuration(%q) = %v, %v, want %v", got, back, err, test.d)
+			}
+		}
+	}
+}
+
+func TestGetDurationMalformed(t *testing.T) {
+	com := &fakeCOM{t: t}
+	settings := com.acquire(com.object("settings", map[string]interface{}{
+		"executionTimeLimit": "PT1H30",
+		"restartInterval":    "PT5M",
+	}))
+	var warned []string
+	w := warnings(func(property string, err error) { warned = append(warned, property) })
+	if got := getDuration(settings, "executionTimeLimit", w); got != 0 {
+		t.Errorf("got duration %v of a malformed duration, want 0", got)
+	}
+	if got := getDuration(settings, "restartInterval", w); got != 5*time.Minute {
+		t.Errorf("got duration %v, want 5m", got)
+	}
+	settings.Release()
+	com.checkReleased()
+	if len(warned) != 1 || warned[0] != "executionTimeLimit" {
+		t.Errorf("got warnings for %v, want a warning for executionTimeLimit", warned)
+	}
+}
@@ -0,0 +1,66 @@
+package taskscheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// COMError is returned if a call of the Task Scheduler 2.0 COM API fails. It wraps the
+// *ole.OleError of the call, use errors.As to inspect it or HRESULT to distinguish
+// failures like access denied from a busy server.
+type COMError struct {
+	Op  string // operation that failed, e.g. "connect to Task Scheduler 2.0"
+	Err error  // error returned by the COM API
+
+	// kind is a sentinel error like ErrAccessDenied that classifies the failure, if any
+	kind error
+}
+
+func (e *COMError) Error() string {
+	return "Could not " + e.Op + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the COM API
+func (e *COMError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is the sentinel error that classifies the failure, so
+// errors.Is(err, ErrAccessDenied) works while the HRESULT is kept
+func (e *COMError) Is(target error) bool {
+	return e.kind != nil && e.kind == target
+}
+
+// HRESULT returns the HRESULT of the failed call, e.g. 0x80070005 for access denied
+func (e *COMError) HRESULT() uint32 {
+	return hresult(e.Err)
+}
+
+// BatchError is returned by batch operations and holds the error of every path that failed
+type BatchError map[string]error
+
+func (e BatchError) Error() string {
+	paths := make([]string, 0, len(e))
+	for path := range e {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	msgs := make([]string, len(paths))
+	for i, path := range paths {
+		msgs[i] = path + ": " + e[path].Error()
+	}
+	return fmt.Sprintf("Could not process %d tasks: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// add records the failure of path, only the first failure of a path is kept
+func (e BatchError) add(path string, err error) {
+	if _, ok := e[path]; !ok {
+		e[path] = err
+	}
+}
+
+// ErrUnsupportedPlatform is returned by all functions that use Task Scheduler 2.0 on other
+// platforms than Windows
+var ErrUnsupportedPlatform = errors.New("Task Scheduler 2.0 is only supported on Windows")
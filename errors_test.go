@@ -0,0 +1,28 @@
+package taskscheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCOMErrorIs(t *testing.T) {
+	err := fmt.Errorf("connect: %w", &COMError{Op: "connect to Task Scheduler 2.0", Err: errFake, kind: ErrAccessDenied})
+	if !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("errors.Is(%v, ErrAccessDenied) = false, want true", err)
+	}
+	if errors.Is(err, ErrServerUnavailable) {
+		t.Errorf("errors.Is(%v, ErrServerUnavailable) = true, want false", err)
+	}
+	if !errors.Is(err, errFake) {
+		t.Errorf("errors.Is(%v, errFake) = false, want the error of the COM API to be wrapped", err)
+	}
+	var comErr *COMError
+	if !errors.As(err, &comErr) || comErr.Err != errFake {
+		t.Errorf("errors.As(%v) did not return the COMError", err)
+	}
+	// Failures without a sentinel error match no sentinel error
+	if errors.Is(&COMError{Op: "connect to Task Scheduler 2.0", Err: errFake}, ErrAccessDenied) {
+		t.Error("COMError without kind matches ErrAccessDenied")
+	}
+}
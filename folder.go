@@ -0,0 +1,6 @@
+package taskscheduler
+
+import "errors"
+
+// ErrFolderNotEmpty is returned by DeleteFolder if the folder still contains tasks or subfolders
+var ErrFolderNotEmpty = errors.New("Folder is not empty")
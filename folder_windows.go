@@ -0,0 +1,181 @@
+package taskscheduler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// GetFolders returns the sorted paths of all folders below the root folder of Windows Task
+// Scheduler 2.0. Folders whose subfolders can not be read are reported by a BatchError, the
+// other folders are still returned.
+func GetFolders() (folders []string, err error) {
+	failed := BatchError{}
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		folders, err = getFolders(ts, failed)
+		return err
+	})
+	if len(folders) > 0 {
+		folders = folders[1:] // without the root folder
+	}
+	sort.Strings(folders)
+	if err == nil && len(failed) > 0 {
+		err = failed
+	}
+	return
+}
+
+// getFolders returns the paths of the root folder and all folders below it, the root folder
+// first. The folders whose subfolders can not be read are recorded in failed.
+func getFolders(ts *ole.IDispatch, failed BatchError) (folders []string, err error) {
+	root, err := getFolder(ts, "\\")
+	if err != nil {
+		return nil, err
+	}
+	defer root.Release()
+	w := &walker{
+		ctx:         context.Background(),
+		foldersOnly: true,
+		onFolder:    func(path string) { folders = append(folders, path) },
+		onError:     failed.add,
+	}
+	_, err = w.getTasksRecursively(comObject{disp: root})
+	return
+}
+
+// CreateFolder creates the folder path, e.g. "\\MyApp\\Jobs", in Windows Task Scheduler 2.0.
+// Missing parent folders are created as well, existing folders are left untouched.
+func CreateFolder(path string) error {
+	return withTaskService(func(ts *ole.IDispatch) error {
+		return createFolder(ts, path)
+	})
+}
+
+// createFolder creates the folder path and its missing parent folders with an ITaskService object
+func createFolder(ts *ole.IDispatch, path string) error {
+	folder, err := getFolder(ts, "\\")
+	if err != nil {
+		return err
+	}
+	current := ""
+	for _, name := range strings.Split(path, "\\") {
+		if name == "" {
+			continue
+		}
+		current += "\\" + name
+		// Open the subfolder if it exists, create it otherwise
+		subfolder, err := getFolder(ts, current)
+		if err == ErrFolderNotFound {
+			var variant *ole.VARIANT
+			if variant, err = oleutil.CallMethod(folder, "CreateFolder", name, nil); err != nil {
+				folder.Release()
+				return &COMError{Op: "create folder in Task Scheduler 2.0", Err: err}
+			}
+			subfolder = variant.ToIDispatch()
+		} else if err != nil {
+			folder.Release()
+			return err
+		}
+		folder.Release()
+		folder = subfolder
+	}
+	folder.Release()
+	return nil
+}
+
+// DeleteFolder deletes the folder path from Windows Task Scheduler 2.0. ErrFolderNotEmpty is
+// returned if the folder still contains tasks or subfolders, unless force is set. In this case
+// all tasks and subfolders are deleted as well.
+func DeleteFolder(path string, force bool) error {
+	path = strings.TrimRight(path, "\\")
+	parentPath, name := splitPath(path)
+	if name == "" {
+		return errors.New("Could not delete root folder of Task Scheduler 2.0")
+	}
+	return withTaskService(func(ts *ole.IDispatch) error {
+		parent, err := getFolder(ts, parentPath)
+		if err != nil {
+			return err
+		}
+		defer parent.Release()
+		if force {
+			folder, err := getFolder(ts, path)
+			if err != nil {
+				return err
+			}
+			err = clearFolder(folder)
+			folder.Release()
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := oleutil.CallMethod(parent, "DeleteFolder", name, int32(0)); err != nil {
+			switch {
+			case isNotFound(err):
+				return ErrFolderNotFound
+			case hresult(err) == hresultDirNotEmpty:
+				return ErrFolderNotEmpty
+			}
+			return &COMError{Op: "delete folder in Task Scheduler 2.0", Err: err}
+		}
+		return nil
+	})
+}
+
+// clearFolder deletes all tasks, including hidden ones, and subfolders of folder.
+func clearFolder(folder *ole.IDispatch) error {
+	// Delete subfolders first
+	variant, err := oleutil.CallMethod(folder, "GetFolders", int64(0))
+	if err != nil {
+		return &COMError{Op: "get subfolders in Task Scheduler 2.0", Err: err}
+	}
+	folders := variant.ToIDispatch()
+	defer folders.Release()
+	var names []string
+	count := getInt(comObject{disp: folders}, "count", nil)
+	for i := 1; i <= count; i++ {
+		index := ole.NewVariant(ole.VT_I4, int64(i))
+		if variant, err = oleutil.GetProperty(folders, "item", &index); err != nil {
+			return &COMError{Op: "get subfolder in Task Scheduler 2.0", Err: err}
+		}
+		subfolder := variant.ToIDispatch()
+		names = append(names, getString(comObject{disp: subfolder}, "name", nil))
+		err = clearFolder(subfolder)
+		subfolder.Release()
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		if _, err := oleutil.CallMethod(folder, "DeleteFolder", name, int32(0)); err != nil {
+			return &COMError{Op: "delete folder in Task Scheduler 2.0", Err: err}
+		}
+	}
+	// Delete tasks
+	if variant, err = oleutil.CallMethod(folder, "GetTasks", int64(taskEnumHidden)); err != nil {
+		return &COMError{Op: "get tasks in Task Scheduler 2.0", Err: err}
+	}
+	tasks := variant.ToIDispatch()
+	defer tasks.Release()
+	names = nil
+	count = getInt(comObject{disp: tasks}, "count", nil)
+	for i := 1; i <= count; i++ {
+		index := ole.NewVariant(ole.VT_I4, int64(i))
+		if variant, err = oleutil.GetProperty(tasks, "item", &index); err != nil {
+			return &COMError{Op: "get task in Task Scheduler 2.0", Err: err}
+		}
+		task := variant.ToIDispatch()
+		names = append(names, getString(comObject{disp: task}, "name", nil))
+		task.Release()
+	}
+	for _, name := range names {
+		if _, err := oleutil.CallMethod(folder, "DeleteTask", name, int32(0)); err != nil {
+			return &COMError{Op: "delete task in Task Scheduler 2.0", Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package taskscheduler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// historyChannel is the event log channel Task Scheduler 2.0 logs the runs of tasks to
+const historyChannel = "Microsoft-Windows-TaskScheduler/Operational"
+
+// Event IDs of the history of a task
+const (
+	HistoryEventTriggeredByUser   = 110
+	HistoryEventStarted           = 100
+	HistoryEventStartFailed       = 101
+	HistoryEventCompleted         = 102
+	HistoryEventActionStartFailed = 103
+	HistoryEventTerminated        = 111
+	HistoryEventActionStarted     = 200
+	HistoryEventActionCompleted   = 201
+)
+
+// HistoryEvent is an event of the history of a task, logged to the
+// Microsoft-Windows-TaskScheduler/Operational event log.
+type HistoryEvent struct {
+	ID         int       `json:"id"` // event ID, e.g. HistoryEventStarted
+	Time       time.Time `json:"time"`
+	Path       string    `json:"path"`
+	InstanceID string    `json:"instanceId"` // GUID of the run of the task
+	ActionName string    `json:"actionName"` // action of HistoryEventActionStarted and HistoryEventActionCompleted
+	ResultCode uint32    `json:"resultCode"` // exit code of the action or HRESULT of the failure
+}
+
+// historyEvent is the XML of an event of the history of a task
+type historyEvent struct {
+	EventID     int `xml:"System>EventID"`
+	TimeCreated struct {
+		SystemTime string `xml:"SystemTime,attr"`
+	} `xml:"System>TimeCreated"`
+	Data []struct {
+		Name  string `xml:"Name,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"EventData>Data"`
+}
+
+// parseHistory parses the events written by wevtutil, which are not enclosed by a root element
+func parseHistory(b []byte) (events []HistoryEvent, err error) {
+	decoder := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		var e historyEvent
+		if err = decoder.Decode(&e); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return events, fmt.Errorf("Could not parse history of task: %v", err)
+		}
+		event := HistoryEvent{ID: e.EventID, Time: parseDateTime(e.TimeCreated.SystemTime)}
+		for _, d := range e.Data {
+			switch d.Name {
+			case "TaskName":
+				event.Path = d.Value
+			case "InstanceId", "TaskInstanceId":
+				event.InstanceID = d.Value
+			case "ActionName":
+				event.ActionName = d.Value
+			case "ResultCode":
+				code, _ := strconv.ParseUint(d.Value, 0, 32)
+				event.ResultCode = uint32(code)
+			}
+		}
+		events = append(events, event)
+	}
+}
+
+// xpathLiteral quotes s as a string literal of an XPath query
+func xpathLiteral(s string) string {
+	if strings.Contains(s, "'") {
+		return `"` + s + `"`
+	}
+	return "'" + s + "'"
+}
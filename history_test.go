@@ -0,0 +1,50 @@
+package taskscheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseHistory(t *testing.T) {
+	// wevtutil writes the events without a root element
+	b := []byte(`<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event"><System>` +
+		`<EventID>200</EventID><TimeCreated SystemTime="2024-01-02T15:04:05.0000000Z"/></System>` +
+		`<EventData><Data Name="TaskName">\Backup</Data><Data Name="ActionName">C:\backup.exe</Data>` +
+		`<Data Name="TaskInstanceId">{A}</Data></EventData></Event>` + "\n" +
+		`<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event"><System>` +
+		`<EventID>201</EventID><TimeCreated SystemTime="2024-01-02T15:05:05.0000000Z"/></System>` +
+		`<EventData><Data Name="TaskName">\Backup</Data><Data Name="InstanceId">{A}</Data>` +
+		`<Data Name="ResultCode">0x80070005</Data></EventData></Event>`)
+	events, err := parseHistory(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HistoryEvent{
+		{
+			ID:         HistoryEventActionStarted,
+			Time:       time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+			Path:       "\\Backup",
+			InstanceID: "{A}",
+			ActionName: "C:\\backup.exe",
+		},
+		{
+			ID:         HistoryEventActionCompleted,
+			Time:       time.Date(2024, 1, 2, 15, 5, 5, 0, time.UTC),
+			Path:       "\\Backup",
+			InstanceID: "{A}",
+			ResultCode: 0x80070005,
+		},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("got events %+v, want %+v", events, want)
+	}
+	if events, err := parseHistory(nil); err != nil || events != nil {
+		t.Errorf("got events %v, %v of an empty history, want none", events, err)
+	}
+	// The events before a malformed event are returned with the error
+	events, err = parseHistory(append(b[:len(b):len(b)], "<Event><System>"...))
+	if err == nil || len(events) != 2 {
+		t.Errorf("got %d events, %v of a malformed history, want 2 events and an error", len(events), err)
+	}
+}
@@ -0,0 +1,29 @@
+package taskscheduler
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// GetTaskHistory returns the events of the Task at path, e.g. "\\MyApp\\MyTask", logged since
+// since, oldest first. The history of Task Scheduler 2.0 must be enabled to log events.
+func GetTaskHistory(path string, since time.Time) ([]HistoryEvent, error) {
+	query := "*[EventData[Data[@Name='TaskName']=" + xpathLiteral(path) + "]"
+	if !since.IsZero() {
+		query += " and System[TimeCreated[@SystemTime>='" + since.UTC().Format("2006-01-02T15:04:05.000Z") + "']]"
+	}
+	query += "]"
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("wevtutil", "qe", historyChannel, "/q:"+query, "/f:xml")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("Could not query history of task %s: %v: %s", path, err, msg)
+		}
+		return nil, fmt.Errorf("Could not query history of task %s: %v", path, err)
+	}
+	return parseHistory(stdout.Bytes())
+}
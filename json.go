@@ -0,0 +1,73 @@
+package taskscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// unmarshalEnum decodes data, the number or the name of a value of an enum with the numbers
+// 0 to max, e.g. 4 or "Running". name returns the name of a number like the String method of
+// the enum. "Unknown" is only decoded if it is the name of 0, since it is the name of all
+// numbers that are not defined.
+func unmarshalEnum(data []byte, enum string, max int, name func(int) string) (int, error) {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		return number, nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return 0, fmt.Errorf("Could not decode %s from %s", enum, data)
+	}
+	for i := 0; i <= max; i++ {
+		if name(i) == s && (s != "Unknown" || i == 0) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("Could not decode %s from unknown name %q", enum, s)
+}
+
+// unmarshalEnums decodes data, a list of numbers or names of an enum like unmarshalEnum. The
+// list is nil if data is null.
+func unmarshalEnums(data []byte, enum string, max int, name func(int) string) ([]int, error) {
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil || list == nil {
+		return nil, err
+	}
+	numbers := make([]int, len(list))
+	for i, raw := range list {
+		var err error
+		if numbers[i], err = unmarshalEnum(raw, enum, max, name); err != nil {
+			return nil, err
+		}
+	}
+	return numbers, nil
+}
+
+// marshalWithType encodes the struct v as JSON object with the additional field "type", e.g.
+// {"type":"Daily",...}, so the concrete type of a Trigger or an Action can be decoded again.
+func marshalWithType(typeName string, v interface{}) (json.RawMessage, error) {
+	fields, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	typeField, err := json.Marshal(typeName)
+	if err != nil {
+		return nil, err
+	}
+	object := append([]byte(`{"type":`), typeField...)
+	if len(fields) > 2 {
+		object = append(object, ',')
+	}
+	return append(object, fields[1:]...), nil
+}
+
+// typeOf returns the "type" field of the JSON object data, it is nil if data is null.
+func typeOf(data []byte) (json.RawMessage, error) {
+	var header struct {
+		Type json.RawMessage `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	return header.Type, nil
+}
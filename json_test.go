@@ -0,0 +1,95 @@
+package taskscheduler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEnumJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		decoded interface{} // pointer to the decoded enum
+		want    interface{}
+	}{
+		{data: `"Running"`, decoded: new(TaskState), want: TaskStateRunning},
+		{data: `4`, decoded: new(TaskState), want: TaskStateRunning},
+		{data: `"Unknown"`, decoded: new(TaskState), want: TaskStateUnknown},
+		{data: `"S4U"`, decoded: new(LogonType), want: LogonTypeS4U},
+		{data: `"Highest"`, decoded: new(RunLevel), want: RunLevelHighest},
+		{data: `"StopExisting"`, decoded: new(InstancesPolicy), want: InstancesStopExisting},
+		{data: `"BelowNormal"`, decoded: new(Priority), want: PriorityBelowNormal},
+		{data: `8`, decoded: new(Priority), want: Priority(8)},
+		{data: `"MonthlyDOW"`, decoded: new(TriggerType), want: TriggerTypeMonthlyDOW},
+		{data: `"ShowMessage"`, decoded: new(ActionType), want: ActionTypeShowMessage},
+		{data: `"SessionUnlock"`, decoded: new(SessionStateChange), want: SessionStateChangeSessionUnlock},
+		{data: `"Run"`, decoded: new(TaskEventType), want: TaskEventRun},
+	}
+	for _, test := range tests {
+		if err := json.Unmarshal([]byte(test.data), test.decoded); err != nil {
+			t.Errorf("could not decode %s: %v", test.data, err)
+			continue
+		}
+		if got := reflect.ValueOf(test.decoded).Elem().Interface(); got != test.want {
+			t.Errorf("decoded %s as %v, want %v", test.data, got, test.want)
+		}
+	}
+	// "Unknown" is the name of all undefined numbers, so it is only decoded for a zero value named "Unknown"
+	for _, data := range []string{`"Unknown"`, `"Exec "`, `true`} {
+		var a ActionType
+		if err := json.Unmarshal([]byte(data), &a); err == nil {
+			t.Errorf("decoded %s as %v without error", data, a)
+		}
+	}
+}
+
+func TestTaskJSON(t *testing.T) {
+	start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	task := Task{
+		Name:        "Backup",
+		Path:        "\\Backup",
+		State:       TaskStateRunning,
+		LastRunTime: start,
+		ActionList:  []ExecAction{{Path: "backup.exe"}},
+		AllActions:  Actions{ExecAction{Path: "backup.exe"}},
+		TriggerList: Triggers{MonthlyDOWTrigger{
+			TaskTrigger:  TaskTrigger{Enabled: true, StartBoundary: start},
+			WeeksOfMonth: []int{LastWeekOfMonth},
+			DaysOfWeek:   Weekdays{time.Sunday},
+			MonthsOfYear: Months{time.June},
+		}},
+		Principal: Principal{UserID: "SYSTEM", LogonType: LogonTypeServiceAccount, RunLevel: RunLevelHighest},
+		Settings:  Settings{Enabled: true, MultipleInstances: InstancesQueue, Priority: PriorityBelowNormal},
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Task
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, task) {
+		t.Errorf("decoded %#v, want %#v", decoded, task)
+	}
+}
+
+func TestTaskDefinitionJSON(t *testing.T) {
+	for _, def := range []TaskDefinition{
+		NewTaskDefinition("Empty"),
+		NewTaskDefinition("Weekly").AddExecAction("backup.exe", "", "").AddWeeklyTrigger(time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC), 1, time.Monday),
+	} {
+		data, err := json.Marshal(def)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded TaskDefinition
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("could not decode %s: %v", data, err)
+		}
+		if !reflect.DeepEqual(decoded, def) {
+			t.Errorf("decoded %#v, want %#v", decoded, def)
+		}
+	}
+}
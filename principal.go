@@ -0,0 +1,107 @@
+package taskscheduler
+
+import "encoding/json"
+
+// LogonType defines how a task logs on as defined by TASK_LOGON_TYPE
+type LogonType int32
+
+// Logon types of Task Scheduler 2.0
+const (
+	LogonTypeNone                       LogonType = 0
+	LogonTypePassword                   LogonType = 1
+	LogonTypeS4U                        LogonType = 2
+	LogonTypeInteractiveToken           LogonType = 3
+	LogonTypeGroup                      LogonType = 4
+	LogonTypeServiceAccount             LogonType = 5
+	LogonTypeInteractiveTokenOrPassword LogonType = 6
+)
+
+// RunLevel defines the privileges a task runs with as defined by TASK_RUNLEVEL_TYPE
+type RunLevel int32
+
+// Run levels of Task Scheduler 2.0
+const (
+	RunLevelLUA     RunLevel = 0 // least privileges
+	RunLevelHighest RunLevel = 1 // highest privileges
+)
+
+// String returns the name of the logon type, e.g. "Password"
+func (l LogonType) String() string {
+	switch l {
+	case LogonTypeNone:
+		return "None"
+	case LogonTypePassword:
+		return "Password"
+	case LogonTypeS4U:
+		return "S4U"
+	case LogonTypeInteractiveToken:
+		return "InteractiveToken"
+	case LogonTypeGroup:
+		return "Group"
+	case LogonTypeServiceAccount:
+		return "ServiceAccount"
+	case LogonTypeInteractiveTokenOrPassword:
+		return "InteractiveTokenOrPassword"
+	}
+	return "Unknown"
+}
+
+// MarshalJSON encodes the logon type as its name, e.g. "Password"
+func (l LogonType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// UnmarshalJSON decodes the logon type from its name or number
+func (l *LogonType) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "logon type", int(LogonTypeInteractiveTokenOrPassword), func(i int) string { return LogonType(i).String() })
+	*l = LogonType(n)
+	return err
+}
+
+// String returns the name of the run level, e.g. "Highest"
+func (r RunLevel) String() string {
+	switch r {
+	case RunLevelLUA:
+		return "LUA"
+	case RunLevelHighest:
+		return "Highest"
+	}
+	return "Unknown"
+}
+
+// MarshalJSON encodes the run level as its name, e.g. "Highest"
+func (r RunLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON decodes the run level from its name or number
+func (r *RunLevel) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "run level", int(RunLevelHighest), func(i int) string { return RunLevel(i).String() })
+	*r = RunLevel(n)
+	return err
+}
+
+// Principal is the security context a task runs in (IPrincipal).
+type Principal struct {
+	UserID    string    `json:"userId"`
+	GroupID   string    `json:"groupId"`
+	LogonType LogonType `json:"logonType"`
+	RunLevel  RunLevel  `json:"runLevel"`
+}
+
+// parsePrincipal reads the IPrincipal of a ITaskDefinition object, the properties that can not be
+// read are recorded by w.
+func parsePrincipal(definition dispatcher, w warnings) (p Principal) {
+	principal, err := getObject(definition, "principal")
+	if err != nil {
+		w.warn("principal", err)
+		return
+	}
+	defer principal.Release()
+	w = w.nested("principal")
+	p.UserID = getString(principal, "userId", w)
+	p.GroupID = getString(principal, "groupId", w)
+	p.LogonType = LogonType(getInt(principal, "logonType", w))
+	p.RunLevel = RunLevel(getInt(principal, "runLevel", w))
+	return
+}
@@ -0,0 +1,53 @@
+package taskscheduler
+
+import "time"
+
+// toInt converts the integer types a VARIANT may contain to int.
+func toInt(v interface{}) int {
+	switch v := v.(type) {
+	case int8:
+		return int(v)
+	case uint8:
+		return int(v)
+	case int16:
+		return int(v)
+	case uint16:
+		return int(v)
+	case int32:
+		return int(v)
+	case uint32:
+		return int(v)
+	case int64:
+		return int(v)
+	case uint64:
+		return int(v)
+	case int:
+		return v
+	case uint:
+		return int(v)
+	}
+	return 0
+}
+
+// toTime converts the date a VARIANT may contain to time.Time. The zero time is returned for
+// the sentinel dates Task Scheduler 2.0 reports if a task never ran or will not run, which are
+// the OLE date 0 (1899-12-30) and 1999-11-30.
+func toTime(v interface{}) time.Time {
+	t, _ := v.(time.Time)
+	if t.Year() < 1900 || (t.Year() == 1999 && t.Month() == time.November && t.Day() == 30 && t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0) {
+		return time.Time{}
+	}
+	return t
+}
+
+// parseDateTime parses a date time of a task definition, e.g. "2006-01-02T15:04:05Z" or
+// "2006-01-02T15:04:05+01:00". The offset is preserved if present, date times without an
+// offset are in the local time of the machine like Task Scheduler 2.0 interprets them.
+// The zero time is returned if s is empty or malformed.
+func parseDateTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	t, _ := time.ParseInLocation("2006-01-02T15:04:05", s, time.Local)
+	return t
+}
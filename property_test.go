@@ -0,0 +1,38 @@
+package taskscheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateTime(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Time
+	}{
+		{s: "2024-01-02T15:04:05Z", want: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{s: "2024-01-02T15:04:05+01:00", want: time.Date(2024, 1, 2, 15, 4, 5, 0, time.FixedZone("", 3600))},
+		{s: "2024-01-02T15:04:05-05:30", want: time.Date(2024, 1, 2, 15, 4, 5, 0, time.FixedZone("", -19800))},
+		{s: "2024-01-02T15:04:05", want: time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local)},
+		{s: "2024-01-02T15:04:05.250", want: time.Date(2024, 1, 2, 15, 4, 5, 250000000, time.Local)},
+		{s: "2024-01-02T15:04:05.1234567Z", want: time.Date(2024, 1, 2, 15, 4, 5, 123456700, time.UTC)},
+		{s: "", want: time.Time{}},
+		{s: "2024-01-02", want: time.Time{}},
+		{s: "not a date", want: time.Time{}},
+	}
+	for _, test := range tests {
+		got := parseDateTime(test.s)
+		if !got.Equal(test.want) {
+			t.Errorf("parseDateTime(%q) = %v, want %v", test.s, got, test.want)
+		}
+		// The offset is preserved, date times without offset are local
+		_, gotOffset := got.Zone()
+		_, wantOffset := test.want.Zone()
+		if gotOffset != wantOffset {
+			t.Errorf("parseDateTime(%q) has offset %d, want %d", test.s, gotOffset, wantOffset)
+		}
+	}
+	if got := parseDateTime("2024-01-02T15:04:05"); got.Location() != time.Local {
+		t.Errorf("parseDateTime without offset is in %v, want %v", got.Location(), time.Local)
+	}
+}
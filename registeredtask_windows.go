@@ -0,0 +1,195 @@
+package taskscheduler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// DeleteTask deletes the Task at path, e.g. "\\MyApp\\MyTask", from Windows Task Scheduler 2.0
+func DeleteTask(path string) error {
+	folderPath, name := splitPath(path)
+	return withTaskService(func(ts *ole.IDispatch) error {
+		folder, err := getFolder(ts, folderPath)
+		if err == ErrFolderNotFound {
+			return ErrTaskNotFound
+		} else if err != nil {
+			return err
+		}
+		defer folder.Release()
+		if _, err := oleutil.CallMethod(folder, "DeleteTask", name, int32(0)); err != nil {
+			if isNotFound(err) {
+				return ErrTaskNotFound
+			}
+			return &COMError{Op: "delete task in Task Scheduler 2.0", Err: err}
+		}
+		return nil
+	})
+}
+
+// GetTask returns the Task at path, e.g. "\\MyApp\\MyTask", in Windows Task Scheduler 2.0
+func GetTask(path string) (task Task, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		task, err = getTask(ts, path)
+		return err
+	})
+	return
+}
+
+func getTask(ts *ole.IDispatch, path string) (Task, error) {
+	registered, err := getRegisteredTask(ts, path)
+	if err != nil {
+		return Task{}, err
+	}
+	defer registered.Release()
+	return parseTask(comObject{disp: registered}), nil
+}
+
+// TaskExists returns true if the Task at path, e.g. "\\MyApp\\MyTask", exists in Windows Task
+// Scheduler 2.0 without reading its details. Only failures like missing permissions are returned as error.
+func TaskExists(path string) (exists bool, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		registered, err := getRegisteredTask(ts, path)
+		if err == ErrTaskNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		registered.Release()
+		exists = true
+		return nil
+	})
+	return
+}
+
+// GetTaskBatch returns the Tasks at paths in Windows Task Scheduler 2.0 keyed by path, connecting
+// only once. Tasks that do not exist are omitted, other failures are returned as BatchError
+// along with the Tasks that could be read.
+func GetTaskBatch(paths []string) (tasks map[string]Task, err error) {
+	tasks = make(map[string]Task, len(paths))
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		errs := BatchError{}
+		for _, path := range paths {
+			task, err := getTask(ts, path)
+			switch err {
+			case nil:
+				tasks[path] = task
+			case ErrTaskNotFound:
+			default:
+				errs[path] = err
+			}
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+		return nil
+	})
+	return
+}
+
+// getRegisteredTask returns the IRegisteredTask object at path or ErrTaskNotFound if it does not exist.
+func getRegisteredTask(ts *ole.IDispatch, path string) (*ole.IDispatch, error) {
+	folderPath, name := splitPath(path)
+	folder, err := getFolder(ts, folderPath)
+	if err == ErrFolderNotFound {
+		return nil, ErrTaskNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	defer folder.Release()
+	return getTaskInFolder(folder, name)
+}
+
+// getTaskInFolder returns the IRegisteredTask object of the task name in an ITaskFolder object
+func getTaskInFolder(folder *ole.IDispatch, name string) (*ole.IDispatch, error) {
+	variant, err := oleutil.CallMethod(folder, "GetTask", name)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, &COMError{Op: "get task in Task Scheduler 2.0", Err: err}
+	}
+	return variant.ToIDispatch(), nil
+}
+
+// EnableTask enables the Task at path in Windows Task Scheduler 2.0
+func EnableTask(path string) error {
+	return setTaskEnabled(path, true)
+}
+
+// DisableTask disables the Task at path in Windows Task Scheduler 2.0
+func DisableTask(path string) error {
+	return setTaskEnabled(path, false)
+}
+
+// SetEnabledInFolder enables or disables all Tasks in the folder path, e.g. "\\MyApp", and its
+// subfolders and returns the number of changed Tasks. Tasks already in the desired state are skipped.
+// Tasks that can not be changed and folders that can not be read do not abort the change of the
+// other Tasks, they are reported by a BatchError.
+func SetEnabledInFolder(path string, enabled bool) (changed int, err error) {
+	return setEnabledInFolder(path, enabled, false)
+}
+
+// SetEnabledInFolderShallow is like SetEnabledInFolder but does not change Tasks in subfolders.
+func SetEnabledInFolderShallow(path string, enabled bool) (changed int, err error) {
+	return setEnabledInFolder(path, enabled, true)
+}
+
+func setEnabledInFolder(path string, enabled, shallow bool) (changed int, err error) {
+	failed := BatchError{}
+	w := &walker{
+		ctx:          context.Background(),
+		shallow:      shallow,
+		hidden:       true,
+		metadataOnly: true, // only the enabled property is needed
+		onTask: func(task dispatcher, t Task) error {
+			if t.Enabled == enabled {
+				return nil
+			}
+			if err := setEnabled(toIDispatch(task), enabled); err != nil {
+				failed[t.Path] = err
+				return nil
+			}
+			changed++
+			return nil
+		},
+		onError: failed.add,
+	}
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		_, err := w.getTasksInFolder(ts, path)
+		return err
+	})
+	if err == nil && len(failed) > 0 {
+		err = failed
+	}
+	return
+}
+
+func setTaskEnabled(path string, enabled bool) error {
+	return withTaskService(func(ts *ole.IDispatch) error {
+		registered, err := getRegisteredTask(ts, path)
+		if err != nil {
+			return err
+		}
+		defer registered.Release()
+		return setEnabled(registered, enabled)
+	})
+}
+
+// setEnabled sets the enabled property of an IRegisteredTask object and verifies the change.
+func setEnabled(registered *ole.IDispatch, enabled bool) error {
+	if _, err := oleutil.PutProperty(registered, "enabled", enabled); err != nil {
+		return &COMError{Op: "set enabled of task in Task Scheduler 2.0", Err: err}
+	}
+	// Read the property back to verify the change took effect
+	variant, err := oleutil.GetProperty(registered, "enabled")
+	if err != nil {
+		return &COMError{Op: "get enabled of task in Task Scheduler 2.0", Err: err}
+	}
+	if actual, _ := variant.Value().(bool); actual != enabled {
+		return errors.New("Could not change enabled of task in Task Scheduler 2.0")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package taskscheduler
+
+import "time"
+
+// RegistrationInfo is the administrative information of a task (IRegistrationInfo).
+type RegistrationInfo struct {
+	Author        string    `json:"author"`
+	Description   string    `json:"description"`
+	Date          time.Time `json:"date"` // zero if the date is not set or malformed
+	Version       string    `json:"version"`
+	Source        string    `json:"source"` // e.g. the component that installed the task
+	URI           string    `json:"uri"`    // e.g. "\\Microsoft\\Windows\\Defrag\\ScheduledDefrag", may differ from the path
+	Documentation string    `json:"documentation"`
+}
+
+// parseRegistrationInfo reads the IRegistrationInfo of a ITaskDefinition object, the properties
+// that can not be read are recorded by w.
+func parseRegistrationInfo(definition dispatcher, w warnings) (r RegistrationInfo) {
+	registrationInfo, err := getObject(definition, "registrationInfo")
+	if err != nil {
+		w.warn("registrationInfo", err)
+		return
+	}
+	defer registrationInfo.Release()
+	w = w.nested("registrationInfo")
+	r.Author = getString(registrationInfo, "author", w)
+	r.Description = getString(registrationInfo, "description", w)
+	r.Date = parseDateTime(getString(registrationInfo, "date", w))
+	r.Version = getString(registrationInfo, "version", w)
+	r.Source = getString(registrationInfo, "source", w)
+	r.URI = getString(registrationInfo, "URI", w)
+	r.Documentation = getString(registrationInfo, "documentation", w)
+	return
+}
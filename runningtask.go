@@ -0,0 +1,44 @@
+package taskscheduler
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTimeout is returned by WaitForTask if the task is still running when the timeout elapsed
+var ErrTimeout = errors.New("Timeout while waiting for task")
+
+// ErrReleased is returned by the methods of a RunningTask that read the instance live, e.g.
+// EnginePID, if the RunningTask does not keep its IRunningTask object. This is the case after
+// Release and for the RunningTasks of GetRunningTasks and RunTask, which close their connection
+// before they return.
+var ErrReleased = errors.New("Running task was released or is not kept by the connection, see Scheduler.GetRunningTasks")
+
+// RunningTask is a running instance of a Task in Windows Task Scheduler 2.0. Only a RunningTask
+// of a connected Scheduler, i.e. of Scheduler.GetRunningTasks or TaskHandle.Run, keeps its
+// IRunningTask object, so CurrentState, EnginePID and CurrentAction can read the instance live.
+// It keeps the object until it is released or the Scheduler is closed. The fields are a snapshot
+// of the instance when it was read.
+type RunningTask struct {
+	Name         string    `json:"name"`
+	Path         string    `json:"path"`
+	InstanceGUID string    `json:"instanceGuid"` // identifies the instance of the running task
+	State        TaskState `json:"state"`
+	StartTime    time.Time `json:"startTime"` // last run time of the task, zero if unknown or queued
+
+	instance // object of the platform
+}
+
+// RunDuration returns how long the instance has been running, 0 if it is not running
+// or its start time is unknown. The state is read again with CurrentState if the RunningTask
+// keeps its IRunningTask object, otherwise the State of the snapshot is used.
+func (r RunningTask) RunDuration() time.Duration {
+	state, err := r.CurrentState()
+	if err != nil {
+		state = r.State
+	}
+	if state != TaskStateRunning || r.StartTime.IsZero() {
+		return 0
+	}
+	return time.Since(r.StartTime)
+}
@@ -0,0 +1,326 @@
+package taskscheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// instance is the IRunningTask object of a RunningTask
+type instance struct {
+	running *ole.IDispatch
+}
+
+// waitInterval is the interval in which WaitForTask and RunTaskAndWatch poll the state of a task
+const waitInterval = 500 * time.Millisecond
+
+// IsTaskRunning returns true if the Task at path, e.g. "\\MyApp\\MyTask", is currently running
+func IsTaskRunning(path string) (running bool, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		registered, err := getRegisteredTask(ts, path)
+		if err != nil {
+			return err
+		}
+		defer registered.Release()
+		variant, err := oleutil.GetProperty(registered, "state")
+		if err != nil {
+			return &COMError{Op: "get state of task in Task Scheduler 2.0", Err: err}
+		}
+		running = TaskState(toInt(variant.Value())) == TaskStateRunning
+		return nil
+	})
+	return
+}
+
+// WaitForTask waits until the Task at path is no longer running or queued and returns the
+// result of its last run. ErrTimeout is returned if it is still running after timeout.
+func WaitForTask(path string, timeout time.Duration) (exitCode uint32, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		registered, err := getRegisteredTask(ts, path)
+		if err != nil {
+			return err
+		}
+		defer registered.Release()
+		deadline := time.Now().Add(timeout)
+		for {
+			state := TaskState(getInt(comObject{disp: registered}, "state", nil))
+			if state != TaskStateRunning && state != TaskStateQueued {
+				break
+			}
+			if !time.Now().Before(deadline) {
+				return ErrTimeout
+			}
+			time.Sleep(waitInterval)
+		}
+		exitCode = uint32(getInt(comObject{disp: registered}, "lastTaskResult", nil))
+		return nil
+	})
+	return
+}
+
+// GetRunningTasks returns all running instances of Tasks in Windows Task Scheduler 2.0,
+// including instances of hidden Tasks. The connection is closed before GetRunningTasks returns,
+// so the RunningTasks do not keep their IRunningTask objects and CurrentState, EnginePID and
+// CurrentAction return ErrReleased. Use Scheduler.GetRunningTasks to read them.
+func GetRunningTasks() (running []RunningTask, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		running, err = getRunningTasks(ts)
+		for i := range running {
+			running[i].Release()
+		}
+		return err
+	})
+	return
+}
+
+// GetRunningTasks returns all running instances of Tasks in Windows Task Scheduler 2.0,
+// including instances of hidden Tasks. The RunningTasks must be released with Release, also if
+// an error is returned because some instances could not be read.
+func (s *Scheduler) GetRunningTasks() ([]RunningTask, error) {
+	if s.ts == nil {
+		return nil, ErrNotConnected
+	}
+	return getRunningTasks(s.ts)
+}
+
+// getRunningTasks returns the running instances of an ITaskService object, they keep
+// their IRunningTask objects. If instances can not be read, the other instances are returned
+// with the error of the first one.
+func getRunningTasks(ts *ole.IDispatch) (running []RunningTask, err error) {
+	variant, err := oleutil.CallMethod(ts, "GetRunningTasks", int32(taskEnumHidden))
+	if err != nil {
+		return nil, &COMError{Op: "get running tasks in Task Scheduler 2.0", Err: err}
+	}
+	collection := variant.ToIDispatch()
+	defer collection.Release()
+	value, err := comObject{disp: collection}.GetProperty("count")
+	if err != nil {
+		return nil, &COMError{Op: "count running tasks in Task Scheduler 2.0", Err: err}
+	}
+	var failed error
+	count := toInt(value)
+	for i := 1; i <= count; i++ {
+		// Get running instance i
+		index := ole.NewVariant(ole.VT_I4, int64(i))
+		if variant, err = oleutil.GetProperty(collection, "item", &index); err != nil {
+			if failed == nil {
+				failed = &COMError{Op: fmt.Sprintf("get running task %d of %d in Task Scheduler 2.0", i, count), Err: err}
+			}
+			continue
+		}
+		r := parseRunningTask(variant.ToIDispatch())
+		// The instance does not know its start time, but it is the last run time of its task
+		if registered, err := getRegisteredTask(ts, r.Path); err == nil {
+			if variant, err = oleutil.GetProperty(registered, "lastRunTime"); err == nil {
+				r.StartTime = toTime(variant.Value())
+			}
+			registered.Release()
+		}
+		running = append(running, r)
+	}
+	return running, failed
+}
+
+// RunTask runs the Task at path immediately, regardless of its triggers. The args
+// replace the $(Arg0), $(Arg1), ... variables in the actions of the task. The connection is
+// closed before RunTask returns, so CurrentState, EnginePID and CurrentAction of the returned
+// RunningTask return ErrReleased. Use TaskHandle.Run to read them.
+func RunTask(path string, args []string) (running RunningTask, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		registered, err := getRegisteredTask(ts, path)
+		if err != nil {
+			return err
+		}
+		defer registered.Release()
+		running, err = runTask(registered, args)
+		running.Release()
+		return err
+	})
+	return
+}
+
+// RunTaskAndWatch runs the Task at path immediately and sends the state of the Task on the
+// returned channel whenever it changes, e.g. Queued, Running and Ready. The channel is closed
+// when the Task is no longer running or queued or ctx is done. An error is returned if the
+// Task can not be run.
+func RunTaskAndWatch(ctx context.Context, path string) (<-chan TaskState, error) {
+	states := make(chan TaskState)
+	started := make(chan error)
+	// The COM objects are used by the goroutine only, since they belong to its thread
+	go func() {
+		defer close(states)
+		err := withTaskService(func(ts *ole.IDispatch) error {
+			registered, err := getRegisteredTask(ts, path)
+			if err != nil {
+				started <- err
+				return nil
+			}
+			defer registered.Release()
+			running, err := runTask(registered, nil)
+			if err != nil {
+				started <- err
+				return nil
+			}
+			running.Release()
+			close(started)
+			ticker := time.NewTicker(waitInterval)
+			defer ticker.Stop()
+			previous := TaskStateUnknown
+			for {
+				state := TaskState(getInt(comObject{disp: registered}, "state", nil))
+				if state != previous {
+					select {
+					case states <- state:
+					case <-ctx.Done():
+						return nil
+					}
+					previous = state
+				}
+				if state != TaskStateRunning && state != TaskStateQueued {
+					return nil
+				}
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		})
+		if err != nil {
+			// Connecting failed
+			started <- err
+		}
+	}()
+	if err := <-started; err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// runTask runs an IRegisteredTask object and returns the started instance, it keeps its
+// IRunningTask object.
+func runTask(registered *ole.IDispatch, args []string) (RunningTask, error) {
+	var params interface{}
+	if len(args) > 0 {
+		params = args
+	}
+	variant, err := oleutil.CallMethod(registered, "Run", params)
+	if err != nil {
+		return RunningTask{}, &COMError{Op: "run task in Task Scheduler 2.0", Err: err}
+	}
+	running := parseRunningTask(variant.ToIDispatch())
+	// A queued instance has not started yet, so its start time is unknown
+	if running.State == TaskStateRunning {
+		running.StartTime = time.Now()
+	}
+	return running, nil
+}
+
+// parseRunningTask reads the properties of an IRunningTask object into a RunningTask that keeps
+// the object.
+func parseRunningTask(running *ole.IDispatch) (r RunningTask) {
+	r.running = running
+	var (
+		variant *ole.VARIANT
+		err     error
+	)
+	if variant, err = oleutil.GetProperty(running, "name"); err == nil {
+		r.Name = variant.ToString()
+	}
+	if variant, err = oleutil.GetProperty(running, "path"); err == nil {
+		r.Path = variant.ToString()
+	}
+	if variant, err = oleutil.GetProperty(running, "instanceGuid"); err == nil {
+		r.InstanceGUID = variant.ToString()
+	}
+	if variant, err = oleutil.GetProperty(running, "state"); err == nil {
+		state, _ := variant.Value().(int32)
+		r.State = TaskState(state)
+	}
+	return
+}
+
+// Release releases the IRunningTask object of the RunningTask. Copies of a RunningTask share
+// the object, so only one of them must be released.
+func (r *RunningTask) Release() {
+	if r.running != nil {
+		r.running.Release()
+		r.running = nil
+	}
+}
+
+// CurrentAction returns the ID of the action that is currently executed by the instance, empty
+// if the action has no ID. It is read again on every call. ErrReleased is returned if the
+// RunningTask does not keep its object, e.g. if it was returned by GetRunningTasks.
+func (r *RunningTask) CurrentAction() (string, error) {
+	if r.running == nil {
+		return "", ErrReleased
+	}
+	value, err := r.refreshed("currentAction")
+	if err != nil {
+		return "", &COMError{Op: "get current action of running task in Task Scheduler 2.0", Err: err}
+	}
+	action, _ := value.(string)
+	return action, nil
+}
+
+// CurrentState returns the state of the instance, e.g. TaskStateQueued before it started. It
+// is read again on every call, unlike State. ErrReleased is returned if the RunningTask does not
+// keep its object, e.g. if it was returned by GetRunningTasks.
+func (r *RunningTask) CurrentState() (TaskState, error) {
+	if r.running == nil {
+		return TaskStateUnknown, ErrReleased
+	}
+	value, err := r.refreshed("state")
+	if err != nil {
+		return TaskStateUnknown, &COMError{Op: "get state of running task in Task Scheduler 2.0", Err: err}
+	}
+	return TaskState(toInt(value)), nil
+}
+
+// EnginePID returns the process ID of the task engine (taskhostw.exe) that runs the instance,
+// 0 if it is queued. It is read again on every call, since it is not known before the instance
+// started. ErrReleased is returned if the RunningTask does not keep its object, e.g. if it was
+// returned by GetRunningTasks.
+func (r *RunningTask) EnginePID() (uint32, error) {
+	if r.running == nil {
+		return 0, ErrReleased
+	}
+	value, err := r.refreshed("enginePID")
+	if err != nil {
+		return 0, &COMError{Op: "get engine PID of running task in Task Scheduler 2.0", Err: err}
+	}
+	return uint32(toInt(value)), nil
+}
+
+// refreshed refreshes the IRunningTask object and returns its property name
+func (r *RunningTask) refreshed(name string) (interface{}, error) {
+	instance := comObject{disp: r.running}
+	if _, err := instance.CallMethod("Refresh"); err != nil {
+		return nil, err
+	}
+	return instance.GetProperty(name)
+}
+
+// StopTask stops all running instances of the Task at path. It succeeds if no instance is running.
+func StopTask(path string) error {
+	return withTaskService(func(ts *ole.IDispatch) error {
+		registered, err := getRegisteredTask(ts, path)
+		if err != nil {
+			return err
+		}
+		defer registered.Release()
+		return stopTask(registered)
+	})
+}
+
+// stopTask stops all running instances of an IRegisteredTask object.
+func stopTask(registered *ole.IDispatch) error {
+	if _, err := oleutil.CallMethod(registered, "Stop", int32(0)); err != nil {
+		return &COMError{Op: "stop task in Task Scheduler 2.0", Err: err}
+	}
+	return nil
+}
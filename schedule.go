@@ -0,0 +1,209 @@
+package taskscheduler
+
+import (
+	"sort"
+	"time"
+)
+
+// scheduleHorizon limits how far NextRuns searches for occurrences of a trigger
+const scheduleHorizon = 10 * 365 * 24 * time.Hour
+
+// NextRuns returns the next n times after which the Task is started by its time, daily, weekly
+// and monthly triggers including their repetitions. Triggers that depend on events, e.g.
+// logon or boot triggers, are ignored. No times are returned if the Task is disabled.
+func (t Task) NextRuns(n int, after time.Time) []time.Time {
+	if !t.Enabled || n <= 0 {
+		return nil
+	}
+	var runs []time.Time
+	for _, trigger := range t.TriggerList {
+		runs = append(runs, nextRuns(trigger, n, after)...)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Before(runs[j]) })
+	// Remove times at which multiple triggers fire
+	unique := runs[:0]
+	for _, r := range runs {
+		if len(unique) == 0 || !unique[len(unique)-1].Equal(r) {
+			unique = append(unique, r)
+		}
+	}
+	if len(unique) > n {
+		unique = unique[:n]
+	}
+	return unique
+}
+
+// nextRuns returns up to n times after which trigger starts a task
+func nextRuns(trigger Trigger, n int, after time.Time) []time.Time {
+	common := trigger.Common()
+	if !common.Enabled || common.StartBoundary.IsZero() {
+		return nil
+	}
+	next := occurrences(trigger, after)
+	if next == nil {
+		return nil
+	}
+	var runs []time.Time
+	base, ok := next()
+	for ok {
+		if !common.EndBoundary.IsZero() && !base.Before(common.EndBoundary) {
+			break
+		}
+		nextBase, nextOk := next()
+		// Repetitions end after their duration, or at the next occurrence if they are indefinite
+		var end time.Time
+		if rep := common.Repetition; rep.Interval > 0 {
+			if rep.Duration > 0 {
+				end = base.Add(rep.Duration)
+			} else if nextOk {
+				end = nextBase
+			}
+		}
+		runs = append(runs, repetitions(base, end, common, n, after)...)
+		if len(runs) >= n {
+			sort.Slice(runs, func(i, j int) bool { return runs[i].Before(runs[j]) })
+			runs = runs[:n]
+			if !nextOk || !nextBase.Before(runs[n-1]) {
+				break
+			}
+		}
+		base, ok = nextBase, nextOk
+	}
+	return runs
+}
+
+// repetitions returns up to n times after after at which a task started at base by a trigger
+// is repeated until end, a zero end repeats indefinitely.
+func repetitions(base, end time.Time, common TaskTrigger, n int, after time.Time) (runs []time.Time) {
+	interval := common.Repetition.Interval
+	if interval <= 0 {
+		if base.After(after) {
+			runs = append(runs, base)
+		}
+		return
+	}
+	// Skip the repetitions before after
+	k := int64(0)
+	if after.After(base) {
+		k = int64(after.Sub(base)/interval) + 1
+	}
+	for ; len(runs) < n; k++ {
+		r := base.Add(time.Duration(k) * interval)
+		if !end.IsZero() && !r.Before(end) {
+			break
+		}
+		if !common.EndBoundary.IsZero() && !r.Before(common.EndBoundary) {
+			break
+		}
+		runs = append(runs, r)
+	}
+	return
+}
+
+// occurrences returns a function that returns the start times of trigger in ascending order.
+// nil is returned for triggers that do not start a task at fixed times.
+func occurrences(trigger Trigger, after time.Time) func() (time.Time, bool) {
+	start := trigger.Common().StartBoundary
+	var match func(day time.Time) bool
+	switch trigger := trigger.(type) {
+	case TimeTrigger:
+		done := false
+		return func() (time.Time, bool) {
+			if done {
+				return time.Time{}, false
+			}
+			done = true
+			return start, true
+		}
+	case DailyTrigger:
+		interval := int64(trigger.DaysInterval)
+		if interval < 1 {
+			interval = 1
+		}
+		match = func(day time.Time) bool {
+			return (dayNumber(day)-dayNumber(start))%interval == 0
+		}
+	case WeeklyTrigger:
+		interval := int64(trigger.WeeksInterval)
+		if interval < 1 {
+			interval = 1
+		}
+		firstWeek := dayNumber(start) - int64(start.Weekday())
+		match = func(day time.Time) bool {
+			return containsWeekday(trigger.DaysOfWeek, day.Weekday()) &&
+				((dayNumber(day)-firstWeek)/7)%interval == 0
+		}
+	case MonthlyTrigger:
+		match = func(day time.Time) bool {
+			if !containsMonth(trigger.MonthsOfYear, day.Month()) {
+				return false
+			}
+			for _, d := range trigger.DaysOfMonth {
+				if d == day.Day() {
+					return true
+				}
+			}
+			return trigger.RunOnLastDayOfMonth && day.AddDate(0, 0, 1).Day() == 1
+		}
+	case MonthlyDOWTrigger:
+		match = func(day time.Time) bool {
+			if !containsMonth(trigger.MonthsOfYear, day.Month()) || !containsWeekday(trigger.DaysOfWeek, day.Weekday()) {
+				return false
+			}
+			week := (day.Day()-1)/7 + 1
+			for _, w := range trigger.WeeksOfMonth {
+				if w == week || (w == LastWeekOfMonth && day.AddDate(0, 0, 7).Month() != day.Month()) {
+					return true
+				}
+			}
+			return false
+		}
+	default:
+		return nil
+	}
+	// Walk the days from the start boundary, keeping its time of day
+	horizon := after
+	if start.After(horizon) {
+		horizon = start
+	}
+	horizon = horizon.Add(scheduleHorizon)
+	day := start
+	return func() (time.Time, bool) {
+		for !day.After(horizon) {
+			current := day
+			day = time.Date(day.Year(), day.Month(), day.Day()+1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+			if match(current) {
+				return current, true
+			}
+		}
+		return time.Time{}, false
+	}
+}
+
+// dayNumber returns the number of days between the Unix epoch and the date of t
+func dayNumber(t time.Time) int64 {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// containsWeekday returns true if day is in days
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// containsMonth returns true if month is in months or months is empty
+func containsMonth(months []time.Month, month time.Month) bool {
+	if len(months) == 0 {
+		return true
+	}
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
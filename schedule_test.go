@@ -0,0 +1,122 @@
+package taskscheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNextRuns(t *testing.T) {
+	// 2024-01-01 is a Monday, 2024 is a leap year
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	day := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2024, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	common := TaskTrigger{Enabled: true, StartBoundary: start}
+	tests := []struct {
+		name     string
+		triggers Triggers
+		n        int
+		after    time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "every second day",
+			triggers: Triggers{DailyTrigger{TaskTrigger: common, DaysInterval: 2}},
+			n:        3,
+			after:    day(1, 1, 0),
+			want:     []time.Time{day(1, 1, 8), day(1, 3, 8), day(1, 5, 8)},
+		},
+		{
+			name: "every second week on monday and wednesday",
+			triggers: Triggers{WeeklyTrigger{
+				TaskTrigger:   common,
+				WeeksInterval: 2,
+				DaysOfWeek:    Weekdays{time.Monday, time.Wednesday},
+			}},
+			n:     4,
+			after: day(1, 1, 9),
+			want:  []time.Time{day(1, 3, 8), day(1, 15, 8), day(1, 17, 8), day(1, 29, 8)},
+		},
+		{
+			name:     "last day of month",
+			triggers: Triggers{MonthlyTrigger{TaskTrigger: common, RunOnLastDayOfMonth: true}},
+			n:        3,
+			after:    day(1, 1, 0),
+			want:     []time.Time{day(1, 31, 8), day(2, 29, 8), day(3, 31, 8)},
+		},
+		{
+			name: "last friday of month",
+			triggers: Triggers{MonthlyDOWTrigger{
+				TaskTrigger:  common,
+				WeeksOfMonth: []int{LastWeekOfMonth},
+				DaysOfWeek:   Weekdays{time.Friday},
+			}},
+			n:     2,
+			after: day(1, 1, 0),
+			want:  []time.Time{day(1, 26, 8), day(2, 23, 8)},
+		},
+		{
+			name: "repetition ends after its duration",
+			triggers: Triggers{TimeTrigger{TaskTrigger: TaskTrigger{
+				Enabled:       true,
+				StartBoundary: start,
+				Repetition:    Repetition{Interval: time.Hour, Duration: 3 * time.Hour},
+			}}},
+			n:     10,
+			after: day(1, 1, 0),
+			want:  []time.Time{day(1, 1, 8), day(1, 1, 9), day(1, 1, 10)},
+		},
+		{
+			name: "indefinite repetition ends at the next occurrence",
+			triggers: Triggers{DailyTrigger{TaskTrigger: TaskTrigger{
+				Enabled:       true,
+				StartBoundary: start,
+				Repetition:    Repetition{Interval: 12 * time.Hour},
+			}, DaysInterval: 1}},
+			n:     3,
+			after: day(1, 1, 0),
+			want:  []time.Time{day(1, 1, 8), day(1, 1, 20), day(1, 2, 8)},
+		},
+		{
+			name: "end boundary",
+			triggers: Triggers{DailyTrigger{TaskTrigger: TaskTrigger{
+				Enabled:       true,
+				StartBoundary: start,
+				EndBoundary:   day(1, 3, 8),
+			}, DaysInterval: 1}},
+			n:     5,
+			after: day(1, 1, 0),
+			want:  []time.Time{day(1, 1, 8), day(1, 2, 8)},
+		},
+		{
+			name: "triggers at the same time",
+			triggers: Triggers{
+				DailyTrigger{TaskTrigger: common, DaysInterval: 1},
+				TimeTrigger{TaskTrigger: TaskTrigger{Enabled: true, StartBoundary: day(1, 2, 8)}},
+			},
+			n:     3,
+			after: day(1, 1, 0),
+			want:  []time.Time{day(1, 1, 8), day(1, 2, 8), day(1, 3, 8)},
+		},
+		{
+			name: "disabled and event triggers",
+			triggers: Triggers{
+				DailyTrigger{TaskTrigger: TaskTrigger{StartBoundary: start}, DaysInterval: 1},
+				BootTrigger{TaskTrigger: common},
+			},
+			n:     3,
+			after: day(1, 1, 0),
+		},
+	}
+	for _, test := range tests {
+		task := Task{Enabled: true, TriggerList: test.triggers}
+		if got := task.NextRuns(test.n, test.after); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got runs %v, want %v", test.name, got, test.want)
+		}
+	}
+	disabled := Task{TriggerList: Triggers{DailyTrigger{TaskTrigger: common, DaysInterval: 1}}}
+	if got := disabled.NextRuns(3, start); got != nil {
+		t.Errorf("got runs %v of a disabled task, want none", got)
+	}
+}
@@ -0,0 +1,35 @@
+package taskscheduler
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotConnected is returned by the methods of a Scheduler that is not connected
+var ErrNotConnected = errors.New("Scheduler is not connected to Task Scheduler 2.0")
+
+// InitializeCOM controls whether the COM API is initialized with CoInitializeEx(COINIT_MULTITHREADED)
+// before and uninitialized after every call of this package. Set it to false if the calling thread
+// already initialized COM itself, e.g. in STA mode for a GUI.
+var InitializeCOM = true
+
+// defaultRetryDelay is the delay before the first retry of Scheduler.Connect
+const defaultRetryDelay = 100 * time.Millisecond
+
+// Scheduler is a connection to Windows Task Scheduler 2.0 that is kept open across calls,
+// so COM is only initialized once. Empty connection parameters connect to the local machine
+// with the token of the current user. The OS thread of the goroutine that calls Connect is
+// locked until Close is called, so a Scheduler must only be used by this goroutine.
+type Scheduler struct {
+	Server   string
+	User     string
+	Domain   string
+	Password string
+	// Retries is the number of times Connect is retried if the server is busy
+	Retries int
+	// RetryDelay is the delay before the first retry of Connect, it is doubled for every further
+	// retry. 100ms are used if it is not set.
+	RetryDelay time.Duration
+
+	session // connection of the platform
+}
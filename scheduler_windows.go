@@ -0,0 +1,134 @@
+package taskscheduler
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/go-ole/go-ole"
+)
+
+// session is the connection of a Scheduler to the COM API
+type session struct {
+	ts          *ole.IDispatch // connected ITaskService object
+	initialized bool           // COM was initialized by Connect
+}
+
+// Connect initializes the COM API and connects to Windows Task Scheduler 2.0. A failed
+// connection is returned as *COMError, rejected credentials and unreachable servers also
+// match ErrAccessDenied and ErrServerUnavailable with errors.Is.
+func (s *Scheduler) Connect() (err error) {
+	if s.ts != nil {
+		return nil
+	}
+	// COM objects must be used on the thread that initialized COM, so the goroutine must not
+	// migrate to another thread until COM is uninitialized
+	runtime.LockOSThread()
+	defer func() {
+		if err != nil {
+			runtime.UnlockOSThread()
+		}
+	}()
+	// Initialize COM API, S_FALSE is returned if it is already initialized on this thread
+	if InitializeCOM {
+		if err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED); err != nil && hresult(err) != hresultFalse {
+			return &COMError{Op: "initialize Windows COM API", Err: err}
+		}
+		defer func() {
+			if err != nil {
+				ole.CoUninitialize()
+			}
+		}()
+	}
+	// Create an ITaskService object
+	unknown, err := ole.CreateInstance(ole.NewGUID("{0F87369F-A4E5-4CFC-BD3E-73E6154572DD}"), nil)
+	if err != nil {
+		return &COMError{Op: "initialize Task Scheduler 2.0", Err: err}
+	}
+	defer unknown.Release()
+	// Convert IUnknown to IDispatch to get more functions like CallMethod()
+	ts, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return &COMError{Op: "prepare Task Scheduler 2.0", Err: err}
+	}
+	// Connect to the Task Scheduler 2.0, retrying with exponential backoff while the server is busy
+	delay := s.RetryDelay
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+	for attempt := 0; ; attempt++ {
+		_, err = ts.CallMethod("Connect", s.Server, s.User, s.Domain, s.Password)
+		if err == nil || attempt >= s.Retries || !isTransient(err) {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	if err != nil {
+		ts.Release()
+		comErr := &COMError{Op: "connect to Task Scheduler 2.0", Err: err}
+		switch hresult(err) {
+		case hresultAccessDenied, hresultLogonFailure:
+			comErr.kind = ErrAccessDenied
+		case hresultServerUnavailable, hresultBadNetPath:
+			comErr.kind = ErrServerUnavailable
+		}
+		return comErr
+	}
+	s.ts = ts
+	s.initialized = InitializeCOM
+	return nil
+}
+
+// isTransient returns true if err is a failure of a COM call that may succeed if it is retried
+func isTransient(err error) bool {
+	switch hresult(err) {
+	case hresultServerTooBusy, hresultCallRejected, hresultRetryLater:
+		return true
+	}
+	return false
+}
+
+// Close releases the connection to Windows Task Scheduler 2.0 and uninitializes the COM API
+func (s *Scheduler) Close() error {
+	if s.ts == nil {
+		return nil
+	}
+	s.ts.Release()
+	s.ts = nil
+	if s.initialized {
+		ole.CoUninitialize()
+	}
+	runtime.UnlockOSThread()
+	return nil
+}
+
+// GetTasks returns a list of all scheduled Tasks in Windows Task Scheduler 2.0
+func (s *Scheduler) GetTasks() ([]Task, error) {
+	if s.ts == nil {
+		return nil, ErrNotConnected
+	}
+	return getTasks(s.ts)
+}
+
+// GetTask returns the Task at path, e.g. "\\MyApp\\MyTask", in Windows Task Scheduler 2.0
+func (s *Scheduler) GetTask(path string) (Task, error) {
+	if s.ts == nil {
+		return Task{}, ErrNotConnected
+	}
+	return getTask(s.ts, path)
+}
+
+// withTaskService initializes the COM API, connects to the local Task Scheduler 2.0 and
+// calls fn with the connected ITaskService object.
+func withTaskService(fn func(ts *ole.IDispatch) error) error {
+	return withScheduler(&Scheduler{}, fn)
+}
+
+// withScheduler connects s, calls fn with its ITaskService object and closes s again.
+func withScheduler(s *Scheduler, fn func(ts *ole.IDispatch) error) error {
+	if err := s.Connect(); err != nil {
+		return err
+	}
+	defer s.Close()
+	return fn(s.ts)
+}
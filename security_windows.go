@@ -0,0 +1,45 @@
+package taskscheduler
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// daclSecurityInformation is the DACL_SECURITY_INFORMATION flag of a security descriptor
+const daclSecurityInformation = 0x4
+
+// GetTaskSDDL returns the discretionary access control list of the Task at path as SDDL string,
+// e.g. "D:(A;;FA;;;BA)(A;;FR;;;AU)"
+func GetTaskSDDL(path string) (sddl string, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		registered, err := getRegisteredTask(ts, path)
+		if err != nil {
+			return err
+		}
+		defer registered.Release()
+		variant, err := oleutil.CallMethod(registered, "GetSecurityDescriptor", int32(daclSecurityInformation))
+		if err != nil {
+			return &COMError{Op: "get security descriptor of task in Task Scheduler 2.0", Err: err}
+		}
+		sddl = variant.ToString()
+		return nil
+	})
+	return
+}
+
+// SetTaskSDDL replaces the security descriptor of the Task at path with sddl, e.g.
+// "D:(A;;FA;;;BA)" to only allow administrators to access the Task. The COMError of an
+// invalid SDDL string contains the reason Windows rejected it.
+func SetTaskSDDL(path, sddl string) error {
+	return withTaskService(func(ts *ole.IDispatch) error {
+		registered, err := getRegisteredTask(ts, path)
+		if err != nil {
+			return err
+		}
+		defer registered.Release()
+		if _, err := oleutil.CallMethod(registered, "SetSecurityDescriptor", sddl, int32(0)); err != nil {
+			return &COMError{Op: "set security descriptor of task in Task Scheduler 2.0", Err: err}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,221 @@
+package taskscheduler
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Settings are the settings the Task Scheduler uses to run a task (ITaskSettings).
+type Settings struct {
+	Enabled                    bool            `json:"enabled"`
+	Hidden                     bool            `json:"hidden"`
+	ExecutionTimeLimit         time.Duration   `json:"executionTimeLimit"` // 0 if the task may run indefinitely
+	RestartPolicy              RestartPolicy   `json:"restartPolicy"`
+	StartWhenAvailable         bool            `json:"startWhenAvailable"`
+	AllowDemandStart           bool            `json:"allowDemandStart"`
+	MultipleInstances          InstancesPolicy `json:"multipleInstances"`
+	Priority                   Priority        `json:"priority"` // 0 (highest) to 10 (lowest)
+	IdleSettings               IdleSettings    `json:"idleSettings"`
+	NetworkSettings            NetworkSettings `json:"networkSettings"`
+	DeleteWhenExpired          bool            `json:"deleteWhenExpired"`          // false if the task is never deleted
+	DeleteExpiredTaskAfter     time.Duration   `json:"deleteExpiredTaskAfter"`     // delay after the last trigger expired
+	DisallowStartIfOnBatteries bool            `json:"disallowStartIfOnBatteries"` // the task is not started on battery power
+	StopIfGoingOnBatteries     bool            `json:"stopIfGoingOnBatteries"`     // the task is stopped when switching to battery power
+	WakeToRun                  bool            `json:"wakeToRun"`                  // the computer is woken up to run the task
+}
+
+// RestartPolicy defines how often a task is restarted if it fails
+type RestartPolicy struct {
+	Count    int           `json:"count"`    // 0 if the task is not restarted
+	Interval time.Duration `json:"interval"` // delay between the restarts
+}
+
+// NetworkSettings define the network that must be available before a task starts (INetworkSettings).
+type NetworkSettings struct {
+	Name string `json:"name"`
+	ID   string `json:"id"` // GUID of the network
+}
+
+// IdleSettings define how a task behaves when the computer is idle (IIdleSettings).
+type IdleSettings struct {
+	IdleDuration  time.Duration `json:"idleDuration"`  // how long the computer must be idle before the task starts
+	WaitTimeout   time.Duration `json:"waitTimeout"`   // how long to wait for the computer to become idle
+	StopOnIdleEnd bool          `json:"stopOnIdleEnd"` // stop the task if the computer is no longer idle
+	RestartOnIdle bool          `json:"restartOnIdle"` // restart the task when the computer is idle again
+}
+
+// InstancesPolicy defines what happens if a task is triggered while it is already running
+// as defined by TASK_INSTANCES_POLICY
+type InstancesPolicy int
+
+// Instances policies of Task Scheduler 2.0
+const (
+	InstancesParallel     InstancesPolicy = 0 // run a new instance in parallel
+	InstancesQueue        InstancesPolicy = 1 // run a new instance after the running ones finished
+	InstancesIgnoreNew    InstancesPolicy = 2 // do not run a new instance (default)
+	InstancesStopExisting InstancesPolicy = 3 // stop the running instance before a new one is run
+)
+
+// String returns the name of the policy, e.g. "IgnoreNew"
+func (p InstancesPolicy) String() string {
+	switch p {
+	case InstancesParallel:
+		return "Parallel"
+	case InstancesQueue:
+		return "Queue"
+	case InstancesIgnoreNew:
+		return "IgnoreNew"
+	case InstancesStopExisting:
+		return "StopExisting"
+	}
+	return "Unknown"
+}
+
+// MarshalJSON encodes the policy as its name, e.g. "IgnoreNew"
+func (p InstancesPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes the policy from its name or number
+func (p *InstancesPolicy) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "instances policy", int(InstancesStopExisting), func(i int) string { return InstancesPolicy(i).String() })
+	*p = InstancesPolicy(n)
+	return err
+}
+
+// Priority is the priority of a task from 0 (highest) to 10 (lowest), it defines the priority
+// class of the process and the priority of the thread the task runs in.
+type Priority int
+
+// Priorities of Task Scheduler 2.0, the values in between share the priority class of the
+// next lower constant, e.g. 3 is above normal like PriorityAboveNormal
+const (
+	PriorityRealtime    Priority = 0
+	PriorityHigh        Priority = 1
+	PriorityAboveNormal Priority = 2
+	PriorityNormal      Priority = 4
+	PriorityBelowNormal Priority = 7 // default priority of new tasks
+	PriorityIdle        Priority = 9
+)
+
+// Win32 priority classes of processes
+const (
+	realtimePriorityClass    = 0x00000100
+	highPriorityClass        = 0x00000080
+	aboveNormalPriorityClass = 0x00008000
+	normalPriorityClass      = 0x00000020
+	belowNormalPriorityClass = 0x00004000
+	idlePriorityClass        = 0x00000040
+)
+
+// PriorityClass returns the Win32 priority class of the process of a task with priority p,
+// e.g. BELOW_NORMAL_PRIORITY_CLASS (0x4000) for PriorityBelowNormal
+func (p Priority) PriorityClass() uint32 {
+	switch {
+	case p <= PriorityRealtime:
+		return realtimePriorityClass
+	case p < PriorityAboveNormal:
+		return highPriorityClass
+	case p < PriorityNormal:
+		return aboveNormalPriorityClass
+	case p < PriorityBelowNormal:
+		return normalPriorityClass
+	case p < PriorityIdle:
+		return belowNormalPriorityClass
+	}
+	return idlePriorityClass
+}
+
+// String returns the name of the priority class of p, e.g. "BelowNormal"
+func (p Priority) String() string {
+	switch p.PriorityClass() {
+	case realtimePriorityClass:
+		return "Realtime"
+	case highPriorityClass:
+		return "High"
+	case aboveNormalPriorityClass:
+		return "AboveNormal"
+	case normalPriorityClass:
+		return "Normal"
+	case belowNormalPriorityClass:
+		return "BelowNormal"
+	}
+	return "Idle"
+}
+
+// MarshalJSON encodes the priority as the name of its priority class, e.g. "BelowNormal"
+func (p Priority) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes the priority from its number or the name of its priority class, which
+// is decoded as the highest priority of the class, e.g. PriorityAboveNormal for "AboveNormal"
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "priority", 10, func(i int) string { return Priority(i).String() })
+	*p = Priority(n)
+	return err
+}
+
+// parseSettings reads the ITaskSettings of a ITaskDefinition object, the properties that can not
+// be read are recorded by w.
+func parseSettings(definition dispatcher, w warnings) (s Settings) {
+	settings, err := getObject(definition, "settings")
+	if err != nil {
+		w.warn("settings", err)
+		return
+	}
+	defer settings.Release()
+	w = w.nested("settings")
+	s.Enabled = getBool(settings, "enabled", w)
+	s.Hidden = getBool(settings, "hidden", w)
+	s.ExecutionTimeLimit = getDuration(settings, "executionTimeLimit", w)
+	s.RestartPolicy = RestartPolicy{
+		Count:    getInt(settings, "restartCount", w),
+		Interval: getDuration(settings, "restartInterval", w),
+	}
+	s.StartWhenAvailable = getBool(settings, "startWhenAvailable", w)
+	s.AllowDemandStart = getBool(settings, "allowDemandStart", w)
+	s.MultipleInstances = InstancesPolicy(getInt(settings, "multipleInstances", w))
+	s.Priority = Priority(getInt(settings, "priority", w))
+	s.IdleSettings = parseIdleSettings(settings, w)
+	deleteExpiredTaskAfter := getString(settings, "deleteExpiredTaskAfter", w)
+	s.DeleteWhenExpired = deleteExpiredTaskAfter != ""
+	if s.DeleteExpiredTaskAfter, err = parseDuration(deleteExpiredTaskAfter); err != nil {
+		w.warn("deleteExpiredTaskAfter", err)
+	}
+	s.NetworkSettings = parseNetworkSettings(settings, w)
+	s.DisallowStartIfOnBatteries = getBool(settings, "disallowStartIfOnBatteries", w)
+	s.StopIfGoingOnBatteries = getBool(settings, "stopIfGoingOnBatteries", w)
+	s.WakeToRun = getBool(settings, "wakeToRun", w)
+	return
+}
+
+// parseIdleSettings reads the IIdleSettings of an ITaskSettings object.
+func parseIdleSettings(settings dispatcher, w warnings) (i IdleSettings) {
+	idleSettings, err := getObject(settings, "idleSettings")
+	if err != nil {
+		w.warn("idleSettings", err)
+		return
+	}
+	defer idleSettings.Release()
+	w = w.nested("idleSettings")
+	i.IdleDuration = getDuration(idleSettings, "idleDuration", w)
+	i.WaitTimeout = getDuration(idleSettings, "waitTimeout", w)
+	i.StopOnIdleEnd = getBool(idleSettings, "stopOnIdleEnd", w)
+	i.RestartOnIdle = getBool(idleSettings, "restartOnIdle", w)
+	return
+}
+
+// parseNetworkSettings reads the INetworkSettings of an ITaskSettings object.
+func parseNetworkSettings(settings dispatcher, w warnings) (n NetworkSettings) {
+	networkSettings, err := getObject(settings, "networkSettings")
+	if err != nil {
+		w.warn("networkSettings", err)
+		return
+	}
+	defer networkSettings.Release()
+	w = w.nested("networkSettings")
+	n.Name = getString(networkSettings, "name", w)
+	n.ID = getString(networkSettings, "id", w)
+	return
+}
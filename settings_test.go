@@ -0,0 +1,31 @@
+package taskscheduler
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPriorityJSON(t *testing.T) {
+	tests := []struct {
+		priority Priority
+		want     string
+	}{
+		{priority: PriorityRealtime, want: `"Realtime"`},
+		{priority: 3, want: `"AboveNormal"`},
+		{priority: PriorityBelowNormal, want: `"BelowNormal"`},
+		{priority: 10, want: `"Idle"`},
+	}
+	for _, test := range tests {
+		got, err := json.Marshal(Settings{Priority: test.priority})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var settings map[string]json.RawMessage
+		if err := json.Unmarshal(got, &settings); err != nil {
+			t.Fatal(err)
+		}
+		if string(settings["priority"]) != test.want {
+			t.Errorf("priority %d is encoded as %s, want %s", test.priority, settings["priority"], test.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package taskscheduler
+
+import "encoding/json"
+
+// TaskState is the state of a task or a running instance as defined by TASK_STATE
+type TaskState int32
+
+// Task states of Task Scheduler 2.0
+const (
+	TaskStateUnknown  TaskState = 0
+	TaskStateDisabled TaskState = 1
+	TaskStateQueued   TaskState = 2
+	TaskStateReady    TaskState = 3
+	TaskStateRunning  TaskState = 4
+)
+
+// String returns the name of the state, e.g. "Running"
+func (s TaskState) String() string {
+	switch s {
+	case TaskStateDisabled:
+		return "Disabled"
+	case TaskStateQueued:
+		return "Queued"
+	case TaskStateReady:
+		return "Ready"
+	case TaskStateRunning:
+		return "Running"
+	}
+	return "Unknown"
+}
+
+// MarshalJSON encodes the state as its name, e.g. "Running"
+func (s TaskState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes the state from its name or number
+func (s *TaskState) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "task state", int(TaskStateRunning), func(i int) string { return TaskState(i).String() })
+	*s = TaskState(n)
+	return err
+}
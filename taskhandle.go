@@ -0,0 +1,10 @@
+package taskscheduler
+
+// TaskHandle is a Task that keeps its IRegisteredTask object of the enumeration, so it can be
+// changed without resolving its path again. A TaskHandle is valid until it is released or its
+// Scheduler is closed.
+type TaskHandle struct {
+	Task
+
+	handle // object of the platform
+}
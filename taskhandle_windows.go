@@ -0,0 +1,89 @@
+package taskscheduler
+
+import (
+	"context"
+
+	"github.com/go-ole/go-ole"
+)
+
+// handle is the IRegisteredTask object of a TaskHandle
+type handle struct {
+	registered *ole.IDispatch
+}
+
+// GetTaskHandles returns handles of all scheduled Tasks in Windows Task Scheduler 2.0.
+// The handles must be released with Release.
+func (s *Scheduler) GetTaskHandles() (handles []*TaskHandle, err error) {
+	if s.ts == nil {
+		return nil, ErrNotConnected
+	}
+	w := &walker{ctx: context.Background(), onTask: func(task dispatcher, t Task) error {
+		// The task is released by the walk, the handle keeps its own reference
+		registered := toIDispatch(task)
+		registered.AddRef()
+		handles = append(handles, &TaskHandle{Task: t, handle: handle{registered: registered}})
+		return nil
+	}}
+	_, err = w.getTasks(s.ts)
+	return
+}
+
+// GetTaskHandle returns a handle of the Task at path, it must be released with Release.
+func (s *Scheduler) GetTaskHandle(path string) (*TaskHandle, error) {
+	if s.ts == nil {
+		return nil, ErrNotConnected
+	}
+	registered, err := getRegisteredTask(s.ts, path)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskHandle{Task: parseTask(comObject{disp: registered}), handle: handle{registered: registered}}, nil
+}
+
+// Enable enables the Task
+func (h *TaskHandle) Enable() error {
+	if h.registered == nil {
+		return ErrNotConnected
+	}
+	if err := setEnabled(h.registered, true); err != nil {
+		return err
+	}
+	h.Enabled = true
+	return nil
+}
+
+// Disable disables the Task
+func (h *TaskHandle) Disable() error {
+	if h.registered == nil {
+		return ErrNotConnected
+	}
+	if err := setEnabled(h.registered, false); err != nil {
+		return err
+	}
+	h.Enabled = false
+	return nil
+}
+
+// Run runs the Task immediately, see RunTask. The returned RunningTask must be released with Release.
+func (h *TaskHandle) Run(args []string) (RunningTask, error) {
+	if h.registered == nil {
+		return RunningTask{}, ErrNotConnected
+	}
+	return runTask(h.registered, args)
+}
+
+// Stop stops all running instances of the Task, see StopTask
+func (h *TaskHandle) Stop() error {
+	if h.registered == nil {
+		return ErrNotConnected
+	}
+	return stopTask(h.registered)
+}
+
+// Release releases the IRegisteredTask object of the handle
+func (h *TaskHandle) Release() {
+	if h.registered != nil {
+		h.registered.Release()
+		h.registered = nil
+	}
+}
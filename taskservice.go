@@ -1,163 +1,163 @@
 package taskscheduler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
-
-	"github.com/go-ole/go-ole"
-	"github.com/go-ole/go-ole/oleutil"
 )
 
+// ErrTaskNotFound is returned if a task does not exist in Windows Task Scheduler 2.0
+var ErrTaskNotFound = errors.New("Task does not exist")
+
+// ErrFolderNotFound is returned if a folder does not exist in Windows Task Scheduler 2.0
+var ErrFolderNotFound = errors.New("Folder does not exist")
+
+// ErrAccessDenied is returned if the credentials are rejected by Windows Task Scheduler 2.0.
+// It is wrapped by a *COMError with the HRESULT of the failure, compare it with errors.Is.
+var ErrAccessDenied = errors.New("Access denied by Task Scheduler 2.0")
+
+// ErrServerUnavailable is returned if the server of Windows Task Scheduler 2.0 can not be reached.
+// It is wrapped by a *COMError with the HRESULT of the failure, compare it with errors.Is.
+var ErrServerUnavailable = errors.New("Task Scheduler 2.0 server is unreachable")
+
 // Task is a task found in Windows Task Scheduler 2.0
 type Task struct {
-	Name        string
-	Path        string
-	Enabled     bool
-	LastRunTime time.Time
-	NextRunTime time.Time
-	ActionList  []ExecAction // Other actions are ignored, we are only interested in Commandline Actions
+	ID                 string           `json:"id"` // hash of the definition, survives renames but not changes
+	Name               string           `json:"name"`
+	Path               string           `json:"path"`
+	Enabled            bool             `json:"enabled"` // enabled flag of the registered task, see EffectivelyEnabled
+	Hidden             bool             `json:"hidden"`  // hidden in the UI, see GetTasksIncludingHidden
+	State              TaskState        `json:"state"`
+	LastRunTime        time.Time        `json:"lastRunTime"`
+	NextRunTime        time.Time        `json:"nextRunTime"`
+	LastTaskResult     uint32           `json:"lastTaskResult"` // exit code or HRESULT of the last run
+	NumberOfMissedRuns int              `json:"numberOfMissedRuns"`
+	RunningInstances   int              `json:"runningInstances"` // number of instances that are currently running
+	ActionList         []ExecAction     `json:"actionList"`       // Only Commandline Actions, see AllActions for all types of actions
+	AllActions         Actions          `json:"allActions"`
+	TriggerList        Triggers         `json:"triggerList"`
+	Principal          Principal        `json:"principal"`
+	RegistrationInfo   RegistrationInfo `json:"registrationInfo"`
+	Settings           Settings         `json:"settings"`
+	ParseWarnings      []string         `json:"parseWarnings"` // properties that could not be read, empty if the Task was read completely
+
+	xml            string // XML of the definition
+	definitionRead bool   // the definition was read, so Settings and the other parts of it are known
 }
 
-// ExecAction is an action defined in a scheduled Task if type IExecAction.
-type ExecAction struct {
-	WorkingDirectory string
-	Path             string
-	Arguments        string
+// XML returns the XML of the definition of the Task as read during the enumeration, see ExportTaskXML.
+func (t Task) XML() string {
+	return t.xml
 }
 
-// GetTasks returns a list of all scheduled Tasks in Windows Task Scheduler 2.0
-func GetTasks() ([]Task, error) {
-	// Initialize COM API
-	if err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED); err != nil {
-		return nil, errors.New("Could not initialize Windows COM API")
-	}
-	defer ole.CoUninitialize()
-	// Create an ITaskService object
-	unknown, err := ole.CreateInstance(ole.NewGUID("{0F87369F-A4E5-4CFC-BD3E-73E6154572DD}"), nil)
-	if err != nil {
-		return nil, errors.New("Could not initialize Task Scheduler 2.0")
-	}
-	defer unknown.Release()
-	// Convert IUnknown to IDispatch to get more functions like CallMethod()
-	ts, err := unknown.QueryInterface(ole.IID_IDispatch)
-	if err != nil {
-		return nil, errors.New("Could not prepare Task Scheduler 2.0")
+// RunDuration returns the time elapsed since the last run time if the Task is currently
+// running, 0 otherwise.
+func (t Task) RunDuration() time.Duration {
+	if t.State != TaskStateRunning || t.LastRunTime.IsZero() {
+		return 0
 	}
-	defer ts.Release()
-	// Connect to the Task Scheduler 2.0
-	if _, err := ts.CallMethod("Connect", "", "", "", ""); err != nil {
-		return nil, errors.New("Could not connect to Task Scheduler 2.0")
+	return time.Since(t.LastRunTime)
+}
+
+// LastRunSucceeded returns true if the last run of the Task succeeded
+func (t Task) LastRunSucceeded() bool {
+	return t.LastTaskResult == 0
+}
+
+// String returns a one-line summary of the Task, e.g. "\\MyApp\\MyTask [enabled] next=2024-01-02 15:04 actions=1"
+func (t Task) String() string {
+	enabled := "enabled"
+	if !t.Enabled {
+		enabled = "disabled"
 	}
-	// Get Root Directory of Task Scheduler 2.0 and get all tasks recursively
-	variant, err := oleutil.CallMethod(ts, "GetFolder", "\\")
-	if err != nil {
-		return nil, errors.New("Could not get root folder in Task Scheduler 2.0")
+	next := "never"
+	if !t.NextRunTime.IsZero() {
+		next = t.NextRunTime.Format("2006-01-02 15:04")
 	}
-	root := variant.ToIDispatch()
-	defer root.Release()
-	return getTasksRecursively(root), nil
+	return fmt.Sprintf("%s [%s] next=%s actions=%d", t.Path, enabled, next, len(t.Actions()))
 }
 
-func getTasksRecursively(folder *ole.IDispatch) (tasks []Task) {
-	var (
-		variant *ole.VARIANT
-		err     error
-	)
-	// Get Tasks in subfolders first
-	if variant, err = oleutil.CallMethod(folder, "GetFolders", int64(0)); err != nil {
-		return
-	}
-	folderIterator := variant.ToIDispatch()
-	if variant, err = oleutil.GetProperty(folderIterator, "count"); err != nil {
-		return
+// HasRun returns true if the Task has run at least once
+func (t Task) HasRun() bool {
+	return !t.LastRunTime.IsZero()
+}
+
+// warn records that the property of the Task could not be read
+func (t *Task) warn(property string, err error) {
+	t.ParseWarnings = append(t.ParseWarnings, fmt.Sprintf("Could not read %s: %v", property, err))
+}
+
+// EffectivelyEnabled returns true if the Task can be started by its triggers. Enabled only
+// reports the flag of the registered task, the Task is still disabled if its state is
+// TaskStateDisabled or Settings.Enabled is not set. Settings are only considered if the
+// definition was read from Task Scheduler 2.0, e.g. not for Tasks of GetTasksMetadataOnly
+// or Tasks that were not read from the Task Scheduler.
+func (t Task) EffectivelyEnabled() bool {
+	if !t.Enabled || t.State == TaskStateDisabled {
+		return false
 	}
-	count, _ := variant.Value().(int32)
-	for i := int32(1); i <= count; i++ {
-		// Get Tasks of subfolder i
-		index := ole.NewVariant(ole.VT_I4, int64(i))
-		if variant, err = oleutil.GetProperty(folderIterator, "item", &index); err != nil {
-			continue
+	return !t.definitionRead || t.Settings.Enabled
+}
+
+// GetTasksByAuthor returns a list of the scheduled Tasks in Windows Task Scheduler 2.0 whose
+// RegistrationInfo.Author contains author, ignoring case.
+func GetTasksByAuthor(author string) ([]Task, error) {
+	author = strings.ToLower(author)
+	return GetTasksFilter(func(t Task) bool {
+		return strings.Contains(strings.ToLower(t.RegistrationInfo.Author), author)
+	})
+}
+
+// GetTasksUsingExecutable returns a list of the scheduled Tasks in Windows Task Scheduler 2.0
+// with an ExecAction that runs exePath, e.g. "C:\\Program Files\\MyApp\\app.exe". Environment
+// variables and quotes of the paths are resolved and the comparison ignores case like Windows.
+func GetTasksUsingExecutable(exePath string) ([]Task, error) {
+	exePath = strings.Trim(expandEnv(exePath), `"`)
+	return GetTasksFilter(func(t Task) bool {
+		for _, a := range t.ActionList {
+			if strings.EqualFold(strings.Trim(a.ExpandedPath(), `"`), exePath) {
+				return true
+			}
 		}
-		subfolder := variant.ToIDispatch()
-		subtasks := getTasksRecursively(subfolder)
-		tasks = append(tasks, subtasks...)
-		subfolder.Release()
-	}
-	folderIterator.Release()
-	// Get Tasks
-	if variant, err = oleutil.CallMethod(folder, "GetTasks", int64(0)); err != nil {
-		return
+		return false
+	})
+}
+
+// GetTasksModifiedSince returns a list of the scheduled Tasks in Windows Task Scheduler 2.0 whose
+// RegistrationInfo.Date is not before t. Tasks without a valid date are returned as well, since
+// they may have changed.
+func GetTasksModifiedSince(t time.Time) ([]Task, error) {
+	return GetTasksFilter(func(task Task) bool {
+		return task.RegistrationInfo.Date.IsZero() || !task.RegistrationInfo.Date.Before(t)
+	})
+}
+
+// splitPath splits the path of a task into the path of its folder and its name.
+func splitPath(path string) (folder, name string) {
+	i := strings.LastIndex(path, "\\")
+	if i < 0 {
+		return "\\", path
 	}
-	taskIterator := variant.ToIDispatch()
-	if variant, err = oleutil.GetProperty(taskIterator, "count"); err != nil {
-		return
+	if i == 0 {
+		return "\\", path[1:]
 	}
-	count, _ = variant.Value().(int32)
-	for i := int32(1); i <= count; i++ {
-		// Get Task i
-		index := ole.NewVariant(ole.VT_I4, int64(i))
-		if variant, err = oleutil.GetProperty(taskIterator, "item", &index); err != nil {
-			continue
-		}
-		task := variant.ToIDispatch()
-		var t Task
-		if variant, err = oleutil.GetProperty(task, "name"); err == nil {
-			t.Name = variant.ToString()
-		}
-		if variant, err = oleutil.GetProperty(task, "path"); err == nil {
-			t.Path = variant.ToString()
-		}
-		if variant, err = oleutil.GetProperty(task, "enabled"); err == nil {
-			t.Enabled, _ = variant.Value().(bool)
-		}
-		if variant, err = oleutil.GetProperty(task, "lastRunTime"); err == nil {
-			t.LastRunTime, _ = variant.Value().(time.Time)
-		}
-		if variant, err = oleutil.GetProperty(task, "nextRunTime"); err == nil {
-			t.NextRunTime, _ = variant.Value().(time.Time)
-		}
-		// Get more details, e.g. actions
-		if variant, err = oleutil.GetProperty(task, "definition"); err == nil {
-			definition := variant.ToIDispatch()
-			if variant, err = oleutil.GetProperty(definition, "actions"); err == nil {
-				actions := variant.ToIDispatch()
-				if variant, err = oleutil.GetProperty(actions, "count"); err == nil {
-					count2, _ := variant.Value().(int32)
-					for i := int32(1); i <= count2; i++ {
-						// Get Action i
-						index := ole.NewVariant(ole.VT_I4, int64(i))
-						if variant, err = oleutil.GetProperty(actions, "item", &index); err != nil {
-							continue
-						}
-						action := variant.ToIDispatch()
-						if variant, err = oleutil.GetProperty(action, "type"); err != nil {
-							action.Release()
-							continue
-						}
-						actionType, _ := variant.Value().(int32)
-						if actionType != 0 { // only handle IExecAction
-							action.Release()
-							continue
-						}
-						var a ExecAction
-						if variant, err = oleutil.GetProperty(action, "workingDirectory"); err == nil {
-							a.WorkingDirectory = variant.ToString()
-						}
-						if variant, err = oleutil.GetProperty(action, "path"); err == nil {
-							a.Path = variant.ToString()
-						}
-						if variant, err = oleutil.GetProperty(action, "arguments"); err == nil {
-							a.Arguments = variant.ToString()
-						}
-						t.ActionList = append(t.ActionList, a)
-						action.Release()
-					}
-				}
-			}
-		}
-		tasks = append(tasks, t)
-		task.Release()
+	return path[:i], path[i+1:]
+}
+
+// uriElement matches the URI of the registration info, which contains the path of the task
+var uriElement = regexp.MustCompile(`(?s)<URI>.*?</URI>`)
+
+// taskID returns a stable identifier of a task. Task Scheduler 2.0 does not expose a GUID of
+// registered tasks, so the SHA-256 hash of the XML definition without its URI is used, which
+// survives renames but changes whenever the definition is changed.
+func taskID(xml string) string {
+	if xml == "" {
+		return ""
 	}
-	taskIterator.Release()
-	return
+	sum := sha256.Sum256([]byte(uriElement.ReplaceAllString(xml, "")))
+	return hex.EncodeToString(sum[:])
 }
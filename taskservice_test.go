@@ -0,0 +1,21 @@
+package taskscheduler
+
+import "testing"
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		path, folder, name string
+	}{
+		{path: "\\Task", folder: "\\", name: "Task"},
+		{path: "\\Sub\\Task", folder: "\\Sub", name: "Task"},
+		{path: "\\Sub\\Deep\\Task", folder: "\\Sub\\Deep", name: "Task"},
+		{path: "Task", folder: "\\", name: "Task"},
+		{path: "\\", folder: "\\", name: ""},
+		{path: "\\Sub\\", folder: "\\Sub", name: ""},
+	}
+	for _, test := range tests {
+		if folder, name := splitPath(test.path); folder != test.folder || name != test.name {
+			t.Errorf("splitPath(%q) = %q, %q, want %q, %q", test.path, folder, name, test.folder, test.name)
+		}
+	}
+}
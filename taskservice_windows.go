@@ -0,0 +1,201 @@
+package taskscheduler
+
+import (
+	"context"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// GetTasks returns a list of all scheduled Tasks in Windows Task Scheduler 2.0
+func GetTasks() (tasks []Task, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		tasks, err = getTasks(ts)
+		return err
+	})
+	return
+}
+
+// GetTasksContext returns a list of all scheduled Tasks in Windows Task Scheduler 2.0. The
+// enumeration is aborted with ctx.Err() as soon as ctx is done.
+func GetTasksContext(ctx context.Context) (tasks []Task, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		tasks, err = getTasksContext(ctx, ts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// GetTasksWithErrors returns a list of all scheduled Tasks in Windows Task Scheduler 2.0 and
+// the failures of folders and tasks that could not be read, e.g. due to missing permissions.
+// The enumeration is complete if no errors are returned.
+func GetTasksWithErrors() (tasks []Task, errs []error) {
+	w := &walker{ctx: context.Background()}
+	err := withTaskService(func(ts *ole.IDispatch) error {
+		var err error
+		tasks, err = w.getTasks(ts)
+		return err
+	})
+	errs = w.errs
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return
+}
+
+// GetTasksFilter returns a list of the scheduled Tasks in Windows Task Scheduler 2.0 for which
+// pred returns true. pred is called for each Task as soon as it is parsed.
+func GetTasksFilter(pred func(Task) bool) (tasks []Task, err error) {
+	w := &walker{ctx: context.Background(), onTask: func(_ dispatcher, t Task) error {
+		if pred(t) {
+			tasks = append(tasks, t)
+		}
+		return nil
+	}}
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		_, err := w.getTasks(ts)
+		return err
+	})
+	return
+}
+
+// WalkTasks calls fn for each scheduled Task in Windows Task Scheduler 2.0 as soon as it is
+// parsed, without collecting the Tasks in memory. The walk is aborted with the error returned
+// by fn.
+func WalkTasks(fn func(Task) error) error {
+	w := &walker{ctx: context.Background(), onTask: func(_ dispatcher, t Task) error {
+		return fn(t)
+	}}
+	return withTaskService(func(ts *ole.IDispatch) error {
+		_, err := w.getTasks(ts)
+		return err
+	})
+}
+
+// GetTasksInFolder returns a list of all scheduled Tasks in the folder path, e.g. "\\Microsoft\\Windows\\Defrag",
+// and its subfolders. ErrFolderNotFound is returned if the folder does not exist.
+func GetTasksInFolder(path string) (tasks []Task, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		tasks, err = (&walker{ctx: context.Background()}).getTasksInFolder(ts, path)
+		return err
+	})
+	return
+}
+
+// GetTasksShallow returns a list of the scheduled Tasks directly in the folder path, tasks in
+// its subfolders are not returned. ErrFolderNotFound is returned if the folder does not exist.
+func GetTasksShallow(path string) (tasks []Task, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		tasks, err = (&walker{ctx: context.Background(), shallow: true}).getTasksInFolder(ts, path)
+		return err
+	})
+	return
+}
+
+// GetTasksIncludingHidden returns a list of all scheduled Tasks in Windows Task Scheduler 2.0
+// including hidden Tasks, e.g. many maintenance tasks of Windows, which GetTasks omits.
+func GetTasksIncludingHidden() (tasks []Task, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		tasks, err = (&walker{ctx: context.Background(), hidden: true}).getTasks(ts)
+		return err
+	})
+	return
+}
+
+// GetTasksMetadataOnly returns a list of all scheduled Tasks in Windows Task Scheduler 2.0 like
+// GetTasks, but only with the properties that are cheap to read, e.g. name, path, state and run
+// times. The definitions are not read, so actions, triggers, principal and settings are empty.
+func GetTasksMetadataOnly() (tasks []Task, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		tasks, err = (&walker{ctx: context.Background(), metadataOnly: true}).getTasks(ts)
+		return err
+	})
+	return
+}
+
+// GetTasksRemote returns a list of all scheduled Tasks in Windows Task Scheduler 2.0 of server.
+// Empty credentials connect with the token of the current user.
+func GetTasksRemote(server, user, domain, password string) (tasks []Task, err error) {
+	err = withScheduler(&Scheduler{Server: server, User: user, Domain: domain, Password: password}, func(ts *ole.IDispatch) error {
+		tasks, err = getTasks(ts)
+		return err
+	})
+	return
+}
+
+func getTasks(ts *ole.IDispatch) ([]Task, error) {
+	return getTasksContext(context.Background(), ts)
+}
+
+func getTasksContext(ctx context.Context, ts *ole.IDispatch) ([]Task, error) {
+	return (&walker{ctx: ctx}).getTasks(ts)
+}
+
+func (w *walker) getTasks(ts *ole.IDispatch) ([]Task, error) {
+	// Get Root Directory of Task Scheduler 2.0 and get all tasks recursively
+	variant, err := oleutil.CallMethod(ts, "GetFolder", "\\")
+	if err != nil {
+		return nil, &COMError{Op: "get root folder in Task Scheduler 2.0", Err: err}
+	}
+	root := variant.ToIDispatch()
+	defer root.Release()
+	return w.getTasksRecursively(comObject{disp: root})
+}
+
+func (w *walker) getTasksInFolder(ts *ole.IDispatch, path string) ([]Task, error) {
+	folder, err := getFolder(ts, path)
+	if err != nil {
+		return nil, err
+	}
+	defer folder.Release()
+	return w.getTasksRecursively(comObject{disp: folder})
+}
+
+// getFolder returns the ITaskFolder object at path or ErrFolderNotFound if it does not exist.
+func getFolder(ts *ole.IDispatch, path string) (*ole.IDispatch, error) {
+	variant, err := oleutil.CallMethod(ts, "GetFolder", path)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrFolderNotFound
+		}
+		return nil, &COMError{Op: "get folder in Task Scheduler 2.0", Err: err}
+	}
+	return variant.ToIDispatch(), nil
+}
+
+// HRESULTs returned by the Task Scheduler 2.0
+const (
+	hresultFalse             = 0x00000001
+	hresultFileNotFound      = 0x80070002
+	hresultPathNotFound      = 0x80070003
+	hresultAccessDenied      = 0x80070005
+	hresultBadNetPath        = 0x80070035
+	hresultDirNotEmpty       = 0x80070091
+	hresultAlreadyExists     = 0x800700B7
+	hresultLogonFailure      = 0x8007052E
+	hresultServerUnavailable = 0x800706BA
+	hresultServerTooBusy     = 0x800706BB
+	hresultCallRejected      = 0x80010001
+	hresultRetryLater        = 0x8001010A
+)
+
+func isNotFound(err error) bool {
+	hr := hresult(err)
+	return hr == hresultFileNotFound || hr == hresultPathNotFound
+}
+
+// hresult returns the HRESULT of a failed COM call. Errors raised by the Task Scheduler
+// are reported as DISP_E_EXCEPTION, the actual HRESULT is stored in the exception info.
+func hresult(err error) uint32 {
+	oleErr, ok := err.(*ole.OleError)
+	if !ok {
+		return 0
+	}
+	if excepInfo, ok := oleErr.SubError().(interface{ SCODE() uint32 }); ok && excepInfo.SCODE() != 0 {
+		return excepInfo.SCODE()
+	}
+	return uint32(oleErr.Code())
+}
@@ -0,0 +1,645 @@
+package taskscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// TriggerType is the type of a Trigger as defined by TASK_TRIGGER_TYPE2
+type TriggerType int32
+
+// Trigger types of Task Scheduler 2.0
+const (
+	TriggerTypeEvent              TriggerType = 0
+	TriggerTypeTime               TriggerType = 1
+	TriggerTypeDaily              TriggerType = 2
+	TriggerTypeWeekly             TriggerType = 3
+	TriggerTypeMonthly            TriggerType = 4
+	TriggerTypeMonthlyDOW         TriggerType = 5
+	TriggerTypeIdle               TriggerType = 6
+	TriggerTypeRegistration       TriggerType = 7
+	TriggerTypeBoot               TriggerType = 8
+	TriggerTypeLogon              TriggerType = 9
+	TriggerTypeSessionStateChange TriggerType = 11
+	TriggerTypeCustom             TriggerType = 12
+)
+
+// String returns the name of the trigger type, e.g. "Daily"
+func (t TriggerType) String() string {
+	switch t {
+	case TriggerTypeEvent:
+		return "Event"
+	case TriggerTypeTime:
+		return "Time"
+	case TriggerTypeDaily:
+		return "Daily"
+	case TriggerTypeWeekly:
+		return "Weekly"
+	case TriggerTypeMonthly:
+		return "Monthly"
+	case TriggerTypeMonthlyDOW:
+		return "MonthlyDOW"
+	case TriggerTypeIdle:
+		return "Idle"
+	case TriggerTypeRegistration:
+		return "Registration"
+	case TriggerTypeBoot:
+		return "Boot"
+	case TriggerTypeLogon:
+		return "Logon"
+	case TriggerTypeSessionStateChange:
+		return "SessionStateChange"
+	case TriggerTypeCustom:
+		return "Custom"
+	}
+	return "Unknown"
+}
+
+// MarshalJSON encodes the trigger type as its name, e.g. "Daily"
+func (t TriggerType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes the trigger type from its name or number
+func (t *TriggerType) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "trigger type", int(TriggerTypeCustom), func(i int) string { return TriggerType(i).String() })
+	*t = TriggerType(n)
+	return err
+}
+
+// Trigger is a trigger defined in a scheduled Task. Use a type switch to get the
+// concrete trigger, e.g. DailyTrigger.
+type Trigger interface {
+	// Type returns the TASK_TRIGGER_TYPE2 of the trigger
+	Type() TriggerType
+	// Common returns the fields shared by all trigger types
+	Common() TaskTrigger
+}
+
+// TaskTrigger contains the fields shared by all trigger types (ITrigger).
+type TaskTrigger struct {
+	Enabled            bool          `json:"enabled"`
+	StartBoundary      time.Time     `json:"startBoundary"`
+	EndBoundary        time.Time     `json:"endBoundary"` // zero if the trigger never expires
+	Repetition         Repetition    `json:"repetition"`
+	ExecutionTimeLimit time.Duration `json:"executionTimeLimit"` // 0 if the limit of the task in Settings applies
+}
+
+// Repetition defines how often a task is restarted after it was started by a trigger
+// (IRepetitionPattern), e.g. every 10 minutes for 1 day.
+type Repetition struct {
+	Interval          time.Duration `json:"interval"`          // 0 if the task is not repeated
+	Duration          time.Duration `json:"duration"`          // 0 if the task is repeated indefinitely
+	StopAtDurationEnd bool          `json:"stopAtDurationEnd"` // stop running instances at the end of Duration
+}
+
+// Common returns the fields shared by all trigger types
+func (t TaskTrigger) Common() TaskTrigger { return t }
+
+// Triggers are the triggers of a Task or a TaskDefinition. They are encoded in JSON with
+// their type, e.g. "type": "Daily", so the concrete triggers are decoded again.
+type Triggers []Trigger
+
+// triggerTypes are the concrete triggers by type, they are decoded by Triggers
+var triggerTypes = map[TriggerType]Trigger{
+	TriggerTypeEvent:              EventTrigger{},
+	TriggerTypeTime:               TimeTrigger{},
+	TriggerTypeDaily:              DailyTrigger{},
+	TriggerTypeWeekly:             WeeklyTrigger{},
+	TriggerTypeMonthly:            MonthlyTrigger{},
+	TriggerTypeMonthlyDOW:         MonthlyDOWTrigger{},
+	TriggerTypeIdle:               IdleTrigger{},
+	TriggerTypeRegistration:       RegistrationTrigger{},
+	TriggerTypeBoot:               BootTrigger{},
+	TriggerTypeLogon:              LogonTrigger{},
+	TriggerTypeSessionStateChange: SessionStateChangeTrigger{},
+}
+
+// MarshalJSON encodes the triggers with their types
+func (triggers Triggers) MarshalJSON() ([]byte, error) {
+	if triggers == nil {
+		return []byte("null"), nil
+	}
+	list := make([]json.RawMessage, len(triggers))
+	for i, t := range triggers {
+		if t == nil {
+			list[i] = json.RawMessage("null")
+			continue
+		}
+		var err error
+		if list[i], err = marshalWithType(t.Type().String(), t); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(list)
+}
+
+// UnmarshalJSON decodes the triggers into their concrete types. Triggers with a "typeCode" are
+// decoded as UnknownTrigger.
+func (triggers *Triggers) UnmarshalJSON(data []byte) error {
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	if list == nil {
+		*triggers = nil
+		return nil
+	}
+	decoded := make(Triggers, len(list))
+	for i, raw := range list {
+		var err error
+		if decoded[i], err = unmarshalTrigger(raw); err != nil {
+			return fmt.Errorf("Could not decode trigger %d: %w", i+1, err)
+		}
+	}
+	*triggers = decoded
+	return nil
+}
+
+func unmarshalTrigger(data []byte) (Trigger, error) {
+	if string(data) == "null" {
+		return nil, nil
+	}
+	var unknown struct {
+		TypeCode *int32 `json:"typeCode"`
+	}
+	if err := json.Unmarshal(data, &unknown); err != nil {
+		return nil, err
+	}
+	if unknown.TypeCode != nil {
+		var t UnknownTrigger
+		err := json.Unmarshal(data, &t)
+		return t, err
+	}
+	typeField, err := typeOf(data)
+	if err != nil {
+		return nil, err
+	}
+	var triggerType TriggerType
+	if err := json.Unmarshal(typeField, &triggerType); err != nil {
+		return nil, err
+	}
+	prototype, ok := triggerTypes[triggerType]
+	if !ok {
+		return nil, fmt.Errorf("Trigger type %s is not supported", triggerType)
+	}
+	t := reflect.New(reflect.TypeOf(prototype))
+	if err := json.Unmarshal(data, t.Interface()); err != nil {
+		return nil, err
+	}
+	return t.Elem().Interface().(Trigger), nil
+}
+
+// Weekdays are the days of the week of a trigger, they are encoded in JSON as their names,
+// e.g. "Monday".
+type Weekdays []time.Weekday
+
+// MarshalJSON encodes the days as their names
+func (days Weekdays) MarshalJSON() ([]byte, error) {
+	if days == nil {
+		return []byte("null"), nil
+	}
+	names := make([]string, len(days))
+	for i, day := range days {
+		names[i] = day.String()
+	}
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON decodes the days from their names or numbers
+func (days *Weekdays) UnmarshalJSON(data []byte) error {
+	numbers, err := unmarshalEnums(data, "weekday", int(time.Saturday), func(i int) string { return time.Weekday(i).String() })
+	if err != nil || numbers == nil {
+		*days = nil
+		return err
+	}
+	decoded := make(Weekdays, len(numbers))
+	for i, n := range numbers {
+		decoded[i] = time.Weekday(n)
+	}
+	*days = decoded
+	return nil
+}
+
+// Months are the months of the year of a trigger, they are encoded in JSON as their names,
+// e.g. "January".
+type Months []time.Month
+
+// MarshalJSON encodes the months as their names
+func (months Months) MarshalJSON() ([]byte, error) {
+	if months == nil {
+		return []byte("null"), nil
+	}
+	names := make([]string, len(months))
+	for i, month := range months {
+		names[i] = month.String()
+	}
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON decodes the months from their names or numbers
+func (months *Months) UnmarshalJSON(data []byte) error {
+	numbers, err := unmarshalEnums(data, "month", int(time.December), func(i int) string { return time.Month(i).String() })
+	if err != nil || numbers == nil {
+		*months = nil
+		return err
+	}
+	decoded := make(Months, len(numbers))
+	for i, n := range numbers {
+		decoded[i] = time.Month(n)
+	}
+	*months = decoded
+	return nil
+}
+
+// EventTrigger starts a task when an event matching Subscription is logged (IEventTrigger).
+type EventTrigger struct {
+	TaskTrigger
+	Subscription string            `json:"subscription"` // XPath query of the event, e.g. <QueryList>...</QueryList>
+	Delay        time.Duration     `json:"delay"`
+	ValueQueries map[string]string `json:"valueQueries"` // XPath queries of event values passed to the task
+}
+
+// Type returns TriggerTypeEvent
+func (EventTrigger) Type() TriggerType { return TriggerTypeEvent }
+
+// TimeTrigger starts a task once at StartBoundary (ITimeTrigger).
+type TimeTrigger struct {
+	TaskTrigger
+}
+
+// Type returns TriggerTypeTime
+func (TimeTrigger) Type() TriggerType { return TriggerTypeTime }
+
+// DailyTrigger starts a task every DaysInterval days (IDailyTrigger).
+type DailyTrigger struct {
+	TaskTrigger
+	DaysInterval int `json:"daysInterval"`
+}
+
+// Type returns TriggerTypeDaily
+func (DailyTrigger) Type() TriggerType { return TriggerTypeDaily }
+
+// WeeklyTrigger starts a task every WeeksInterval weeks on DaysOfWeek (IWeeklyTrigger).
+type WeeklyTrigger struct {
+	TaskTrigger
+	WeeksInterval int      `json:"weeksInterval"`
+	DaysOfWeek    Weekdays `json:"daysOfWeek"`
+}
+
+// Type returns TriggerTypeWeekly
+func (WeeklyTrigger) Type() TriggerType { return TriggerTypeWeekly }
+
+// LastWeekOfMonth is used in MonthlyDOWTrigger.WeeksOfMonth for the last week of a month
+const LastWeekOfMonth = 5
+
+// MonthlyTrigger starts a task on DaysOfMonth in MonthsOfYear (IMonthlyTrigger).
+type MonthlyTrigger struct {
+	TaskTrigger
+	DaysOfMonth         []int  `json:"daysOfMonth"` // 1 to 31
+	MonthsOfYear        Months `json:"monthsOfYear"`
+	RunOnLastDayOfMonth bool   `json:"runOnLastDayOfMonth"`
+}
+
+// Type returns TriggerTypeMonthly
+func (MonthlyTrigger) Type() TriggerType { return TriggerTypeMonthly }
+
+// MonthlyDOWTrigger starts a task on DaysOfWeek in WeeksOfMonth in MonthsOfYear (IMonthlyDOWTrigger).
+type MonthlyDOWTrigger struct {
+	TaskTrigger
+	WeeksOfMonth []int    `json:"weeksOfMonth"` // 1 to 4 or LastWeekOfMonth
+	DaysOfWeek   Weekdays `json:"daysOfWeek"`
+	MonthsOfYear Months   `json:"monthsOfYear"`
+}
+
+// Type returns TriggerTypeMonthlyDOW
+func (MonthlyDOWTrigger) Type() TriggerType { return TriggerTypeMonthlyDOW }
+
+// IdleTrigger starts a task when the computer becomes idle, see Settings.IdleSettings (IIdleTrigger).
+type IdleTrigger struct {
+	TaskTrigger
+}
+
+// Type returns TriggerTypeIdle
+func (IdleTrigger) Type() TriggerType { return TriggerTypeIdle }
+
+// RegistrationTrigger starts a task once when it is registered or updated (IRegistrationTrigger).
+type RegistrationTrigger struct {
+	TaskTrigger
+	Delay time.Duration `json:"delay"`
+}
+
+// Type returns TriggerTypeRegistration
+func (RegistrationTrigger) Type() TriggerType { return TriggerTypeRegistration }
+
+// BootTrigger starts a task Delay after the system is booted (IBootTrigger).
+type BootTrigger struct {
+	TaskTrigger
+	Delay time.Duration `json:"delay"`
+}
+
+// Type returns TriggerTypeBoot
+func (BootTrigger) Type() TriggerType { return TriggerTypeBoot }
+
+// LogonTrigger starts a task Delay after UserID logs on, any user if UserID is empty (ILogonTrigger).
+type LogonTrigger struct {
+	TaskTrigger
+	UserID string        `json:"userId"`
+	Delay  time.Duration `json:"delay"`
+}
+
+// Type returns TriggerTypeLogon
+func (LogonTrigger) Type() TriggerType { return TriggerTypeLogon }
+
+// SessionStateChange is the change of a session as defined by TASK_SESSION_STATE_CHANGE_TYPE
+type SessionStateChange int32
+
+// Session state changes of Task Scheduler 2.0
+const (
+	SessionStateChangeConsoleConnect    SessionStateChange = 1
+	SessionStateChangeConsoleDisconnect SessionStateChange = 2
+	SessionStateChangeRemoteConnect     SessionStateChange = 3
+	SessionStateChangeRemoteDisconnect  SessionStateChange = 4
+	SessionStateChangeSessionLock       SessionStateChange = 7
+	SessionStateChangeSessionUnlock     SessionStateChange = 8
+)
+
+// String returns the name of the session state change, e.g. "SessionLock"
+func (c SessionStateChange) String() string {
+	switch c {
+	case SessionStateChangeConsoleConnect:
+		return "ConsoleConnect"
+	case SessionStateChangeConsoleDisconnect:
+		return "ConsoleDisconnect"
+	case SessionStateChangeRemoteConnect:
+		return "RemoteConnect"
+	case SessionStateChangeRemoteDisconnect:
+		return "RemoteDisconnect"
+	case SessionStateChangeSessionLock:
+		return "SessionLock"
+	case SessionStateChangeSessionUnlock:
+		return "SessionUnlock"
+	}
+	return "Unknown"
+}
+
+// MarshalJSON encodes the session state change as its name, e.g. "SessionLock"
+func (c SessionStateChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON decodes the session state change from its name or number
+func (c *SessionStateChange) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "session state change", int(SessionStateChangeSessionUnlock), func(i int) string { return SessionStateChange(i).String() })
+	*c = SessionStateChange(n)
+	return err
+}
+
+// SessionStateChangeTrigger starts a task when the session of UserID changes, e.g. on lock or
+// remote connect, any user if UserID is empty (ISessionStateChangeTrigger).
+type SessionStateChangeTrigger struct {
+	TaskTrigger
+	StateChange SessionStateChange `json:"stateChange"`
+	UserID      string             `json:"userId"`
+	Delay       time.Duration      `json:"delay"`
+}
+
+// Type returns TriggerTypeSessionStateChange
+func (SessionStateChangeTrigger) Type() TriggerType { return TriggerTypeSessionStateChange }
+
+// UnknownTrigger is a trigger of a type that is not supported by this package.
+type UnknownTrigger struct {
+	TaskTrigger
+	TypeCode int32 `json:"typeCode"` // raw TASK_TRIGGER_TYPE2
+}
+
+// Type returns the raw type of the trigger
+func (t UnknownTrigger) Type() TriggerType { return TriggerType(t.TypeCode) }
+
+// lastWeekOfMonthBit is the bit of the last week in the WeeksOfMonth bitmask
+const lastWeekOfMonthBit = 0x10
+
+// decodeDaysOfMonth decodes a DaysOfMonth bitmask, day 1 = 1, day 2 = 2, day 3 = 4, ...
+func decodeDaysOfMonth(mask int) (days []int) {
+	for day := 1; day <= 31; day++ {
+		if mask&(1<<uint(day-1)) != 0 {
+			days = append(days, day)
+		}
+	}
+	return
+}
+
+// encodeDaysOfMonth encodes days into a DaysOfMonth bitmask
+func encodeDaysOfMonth(days []int) (mask int) {
+	for _, day := range days {
+		if day >= 1 && day <= 31 {
+			mask |= 1 << uint(day-1)
+		}
+	}
+	return
+}
+
+// decodeMonthsOfYear decodes a MonthsOfYear bitmask, January = 1, February = 2, March = 4, ...
+func decodeMonthsOfYear(mask int) (months []time.Month) {
+	for month := time.January; month <= time.December; month++ {
+		if mask&(1<<uint(month-1)) != 0 {
+			months = append(months, month)
+		}
+	}
+	return
+}
+
+// encodeMonthsOfYear encodes months into a MonthsOfYear bitmask
+func encodeMonthsOfYear(months []time.Month) (mask int) {
+	for _, month := range months {
+		if month >= time.January && month <= time.December {
+			mask |= 1 << uint(month-1)
+		}
+	}
+	return
+}
+
+// decodeWeeksOfMonth decodes a WeeksOfMonth bitmask, first = 1, second = 2, third = 4,
+// fourth = 8 and last = 0x10 which is decoded to LastWeekOfMonth.
+func decodeWeeksOfMonth(mask int) (weeks []int) {
+	for week := 1; week <= LastWeekOfMonth; week++ {
+		if mask&(1<<uint(week-1)) != 0 {
+			weeks = append(weeks, week)
+		}
+	}
+	return
+}
+
+// encodeWeeksOfMonth encodes weeks into a WeeksOfMonth bitmask
+func encodeWeeksOfMonth(weeks []int) (mask int) {
+	for _, week := range weeks {
+		if week >= 1 && week <= LastWeekOfMonth {
+			mask |= 1 << uint(week-1)
+		}
+	}
+	return
+}
+
+// decodeDaysOfWeek decodes a DaysOfWeek bitmask, Sunday = 1, Monday = 2, Tuesday = 4, ...
+func decodeDaysOfWeek(mask int) (days []time.Weekday) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if mask&(1<<uint(day)) != 0 {
+			days = append(days, day)
+		}
+	}
+	return
+}
+
+// encodeDaysOfWeek encodes days into a DaysOfWeek bitmask
+func encodeDaysOfWeek(days []time.Weekday) (mask int) {
+	for _, day := range days {
+		if day >= time.Sunday && day <= time.Saturday {
+			mask |= 1 << uint(day)
+		}
+	}
+	return
+}
+
+// parseTriggers reads the ITriggerCollection of a ITaskDefinition object, the properties that
+// can not be read are recorded by w, e.g. "triggers[1].startBoundary".
+func parseTriggers(definition dispatcher, w warnings) (triggers []Trigger) {
+	collection, err := getObject(definition, "triggers")
+	if err != nil {
+		w.warn("triggers", err)
+		return
+	}
+	defer collection.Release()
+	count := getInt(collection, "count", w.nested("triggers"))
+	for i := 1; i <= count; i++ {
+		// Get Trigger i
+		name := fmt.Sprintf("triggers[%d]", i)
+		trigger, err := getObject(collection, "item", int32(i))
+		if err != nil {
+			w.warn(name, err)
+			continue
+		}
+		triggers = append(triggers, parseTrigger(trigger, w.nested(name)))
+		trigger.Release()
+	}
+	return
+}
+
+// parseTrigger reads an ITrigger object into the concrete Trigger of its type.
+func parseTrigger(trigger dispatcher, w warnings) Trigger {
+	common := TaskTrigger{
+		Enabled:            getBool(trigger, "enabled", w),
+		StartBoundary:      parseDateTime(getString(trigger, "startBoundary", w)),
+		EndBoundary:        parseDateTime(getString(trigger, "endBoundary", w)),
+		Repetition:         parseRepetition(trigger, w),
+		ExecutionTimeLimit: getDuration(trigger, "executionTimeLimit", w),
+	}
+	typeCode := int32(getInt(trigger, "type", w))
+	switch TriggerType(typeCode) {
+	case TriggerTypeEvent:
+		return EventTrigger{
+			TaskTrigger:  common,
+			Subscription: getString(trigger, "subscription", w),
+			Delay:        getDuration(trigger, "delay", w),
+			ValueQueries: parseNamedValues(trigger, "valueQueries", w),
+		}
+	case TriggerTypeTime:
+		return TimeTrigger{TaskTrigger: common}
+	case TriggerTypeDaily:
+		return DailyTrigger{
+			TaskTrigger:  common,
+			DaysInterval: getInt(trigger, "daysInterval", w),
+		}
+	case TriggerTypeWeekly:
+		return WeeklyTrigger{
+			TaskTrigger:   common,
+			WeeksInterval: getInt(trigger, "weeksInterval", w),
+			DaysOfWeek:    decodeDaysOfWeek(getInt(trigger, "daysOfWeek", w)),
+		}
+	case TriggerTypeMonthly:
+		return MonthlyTrigger{
+			TaskTrigger:         common,
+			DaysOfMonth:         decodeDaysOfMonth(getInt(trigger, "daysOfMonth", w)),
+			MonthsOfYear:        decodeMonthsOfYear(getInt(trigger, "monthsOfYear", w)),
+			RunOnLastDayOfMonth: getBool(trigger, "runOnLastDayOfMonth", w),
+		}
+	case TriggerTypeMonthlyDOW:
+		weeks := getInt(trigger, "weeksOfMonth", w)
+		if getBool(trigger, "runOnLastWeekOfMonth", w) {
+			weeks |= lastWeekOfMonthBit
+		}
+		return MonthlyDOWTrigger{
+			TaskTrigger:  common,
+			WeeksOfMonth: decodeWeeksOfMonth(weeks),
+			DaysOfWeek:   decodeDaysOfWeek(getInt(trigger, "daysOfWeek", w)),
+			MonthsOfYear: decodeMonthsOfYear(getInt(trigger, "monthsOfYear", w)),
+		}
+	case TriggerTypeIdle:
+		return IdleTrigger{TaskTrigger: common}
+	case TriggerTypeRegistration:
+		return RegistrationTrigger{
+			TaskTrigger: common,
+			Delay:       getDuration(trigger, "delay", w),
+		}
+	case TriggerTypeBoot:
+		return BootTrigger{
+			TaskTrigger: common,
+			Delay:       getDuration(trigger, "delay", w),
+		}
+	case TriggerTypeLogon:
+		return LogonTrigger{
+			TaskTrigger: common,
+			UserID:      getString(trigger, "userId", w),
+			Delay:       getDuration(trigger, "delay", w),
+		}
+	case TriggerTypeSessionStateChange:
+		return SessionStateChangeTrigger{
+			TaskTrigger: common,
+			StateChange: SessionStateChange(getInt(trigger, "stateChange", w)),
+			UserID:      getString(trigger, "userId", w),
+			Delay:       getDuration(trigger, "delay", w),
+		}
+	}
+	return UnknownTrigger{TaskTrigger: common, TypeCode: typeCode}
+}
+
+// parseNamedValues reads the ITaskNamedValueCollection property name of d.
+func parseNamedValues(d dispatcher, name string, w warnings) (values map[string]string) {
+	collection, err := getObject(d, name)
+	if err != nil {
+		w.warn(name, err)
+		return
+	}
+	defer collection.Release()
+	count := getInt(collection, "count", w.nested(name))
+	for i := 1; i <= count; i++ {
+		item := fmt.Sprintf("%s[%d]", name, i)
+		pair, err := getObject(collection, "item", int32(i))
+		if err != nil {
+			w.warn(item, err)
+			continue
+		}
+		if values == nil {
+			values = make(map[string]string)
+		}
+		values[getString(pair, "name", w.nested(item))] = getString(pair, "value", w.nested(item))
+		pair.Release()
+	}
+	return
+}
+
+// parseRepetition reads the IRepetitionPattern of an ITrigger object.
+func parseRepetition(trigger dispatcher, w warnings) (r Repetition) {
+	repetition, err := getObject(trigger, "repetition")
+	if err != nil {
+		w.warn("repetition", err)
+		return
+	}
+	defer repetition.Release()
+	w = w.nested("repetition")
+	r.Interval = getDuration(repetition, "interval", w)
+	r.Duration = getDuration(repetition, "duration", w)
+	r.StopAtDurationEnd = getBool(repetition, "stopAtDurationEnd", w)
+	return
+}
@@ -0,0 +1,170 @@
+package taskscheduler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDaysOfWeek(t *testing.T) {
+	tests := []struct {
+		days    []time.Weekday
+		mask    int
+		decoded []time.Weekday // days without duplicates and out of range weekdays in ascending order
+	}{
+		{days: nil, mask: 0, decoded: nil},
+		{days: []time.Weekday{time.Sunday}, mask: 0x01, decoded: []time.Weekday{time.Sunday}},
+		{days: []time.Weekday{time.Monday, time.Wednesday, time.Friday}, mask: 0x2A, decoded: []time.Weekday{time.Monday, time.Wednesday, time.Friday}},
+		{days: []time.Weekday{time.Saturday, time.Sunday}, mask: 0x41, decoded: []time.Weekday{time.Sunday, time.Saturday}},
+		{days: []time.Weekday{time.Tuesday, time.Tuesday}, mask: 0x04, decoded: []time.Weekday{time.Tuesday}},
+		{days: []time.Weekday{-1, 7, time.Thursday}, mask: 0x10, decoded: []time.Weekday{time.Thursday}},
+		{days: []time.Weekday{0, 1, 2, 3, 4, 5, 6}, mask: 0x7F, decoded: []time.Weekday{0, 1, 2, 3, 4, 5, 6}},
+	}
+	for _, test := range tests {
+		mask := encodeDaysOfWeek(test.days)
+		if mask != test.mask {
+			t.Errorf("encodeDaysOfWeek(%v) = %#x, want %#x", test.days, mask, test.mask)
+		}
+		if decoded := decodeDaysOfWeek(mask); !reflect.DeepEqual(decoded, test.decoded) {
+			t.Errorf("decodeDaysOfWeek(%#x) = %v, want %v", mask, decoded, test.decoded)
+		}
+	}
+}
+
+func TestMonthsOfYear(t *testing.T) {
+	tests := []struct {
+		months  []time.Month
+		mask    int
+		decoded []time.Month
+	}{
+		{months: nil, mask: 0, decoded: nil},
+		{months: []time.Month{time.January}, mask: 0x001, decoded: []time.Month{time.January}},
+		{months: []time.Month{time.December, time.June}, mask: 0x820, decoded: []time.Month{time.June, time.December}},
+		{months: []time.Month{0, 13, time.March}, mask: 0x004, decoded: []time.Month{time.March}},
+		{months: []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, mask: 0xFFF, decoded: []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}},
+	}
+	for _, test := range tests {
+		mask := encodeMonthsOfYear(test.months)
+		if mask != test.mask {
+			t.Errorf("encodeMonthsOfYear(%v) = %#x, want %#x", test.months, mask, test.mask)
+		}
+		if decoded := decodeMonthsOfYear(mask); !reflect.DeepEqual(decoded, test.decoded) {
+			t.Errorf("decodeMonthsOfYear(%#x) = %v, want %v", mask, decoded, test.decoded)
+		}
+	}
+}
+
+func TestDaysOfMonth(t *testing.T) {
+	tests := []struct {
+		days    []int
+		mask    int
+		decoded []int
+	}{
+		{days: nil, mask: 0, decoded: nil},
+		{days: []int{1}, mask: 0x1, decoded: []int{1}},
+		{days: []int{31, 15, 1}, mask: 0x40004001, decoded: []int{1, 15, 31}},
+		{days: []int{0, 32, -1, 2}, mask: 0x2, decoded: []int{2}},
+	}
+	for _, test := range tests {
+		mask := encodeDaysOfMonth(test.days)
+		if mask != test.mask {
+			t.Errorf("encodeDaysOfMonth(%v) = %#x, want %#x", test.days, mask, test.mask)
+		}
+		if decoded := decodeDaysOfMonth(mask); !reflect.DeepEqual(decoded, test.decoded) {
+			t.Errorf("decodeDaysOfMonth(%#x) = %v, want %v", mask, decoded, test.decoded)
+		}
+	}
+}
+
+func TestWeeksOfMonth(t *testing.T) {
+	tests := []struct {
+		weeks   []int
+		mask    int
+		decoded []int
+	}{
+		{weeks: nil, mask: 0, decoded: nil},
+		{weeks: []int{1, 3}, mask: 0x05, decoded: []int{1, 3}},
+		{weeks: []int{LastWeekOfMonth}, mask: lastWeekOfMonthBit, decoded: []int{LastWeekOfMonth}},
+		{weeks: []int{LastWeekOfMonth, 4, 2}, mask: 0x1A, decoded: []int{2, 4, LastWeekOfMonth}},
+		{weeks: []int{0, 6, 1}, mask: 0x01, decoded: []int{1}},
+	}
+	for _, test := range tests {
+		mask := encodeWeeksOfMonth(test.weeks)
+		if mask != test.mask {
+			t.Errorf("encodeWeeksOfMonth(%v) = %#x, want %#x", test.weeks, mask, test.mask)
+		}
+		if decoded := decodeWeeksOfMonth(mask); !reflect.DeepEqual(decoded, test.decoded) {
+			t.Errorf("decodeWeeksOfMonth(%#x) = %v, want %v", mask, decoded, test.decoded)
+		}
+	}
+}
+
+func TestTriggerJSON(t *testing.T) {
+	start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	triggers := Triggers{
+		TimeTrigger{TaskTrigger: TaskTrigger{Enabled: true, StartBoundary: start}},
+		IdleTrigger{TaskTrigger: TaskTrigger{Enabled: true, StartBoundary: start}},
+		WeeklyTrigger{WeeksInterval: 2, DaysOfWeek: Weekdays{time.Monday, time.Friday}},
+		MonthlyTrigger{DaysOfMonth: []int{1}, MonthsOfYear: Months{time.March}},
+		SessionStateChangeTrigger{StateChange: SessionStateChangeSessionLock},
+		UnknownTrigger{TypeCode: 42},
+		nil,
+	}
+	data, err := json.Marshal(triggers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"Time", "Idle", "Weekly", "Monthly", "SessionStateChange", "Unknown"} {
+		if fields[i]["type"] != want {
+			t.Errorf("%T is encoded with type %v, want %q", triggers[i], fields[i]["type"], want)
+		}
+		// The fields of the trigger are still encoded
+		if _, ok := fields[i]["startBoundary"]; !ok {
+			t.Errorf("%T is encoded without its common fields: %s", triggers[i], data)
+		}
+	}
+	if days := fields[2]["daysOfWeek"]; !reflect.DeepEqual(days, []interface{}{"Monday", "Friday"}) {
+		t.Errorf("days of week are encoded as %v", days)
+	}
+	if months := fields[3]["monthsOfYear"]; !reflect.DeepEqual(months, []interface{}{"March"}) {
+		t.Errorf("months of year are encoded as %v", months)
+	}
+	var decoded Triggers
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, triggers) {
+		t.Errorf("decoded %#v, want %#v", decoded, triggers)
+	}
+}
+
+func TestTriggerJSONNumbers(t *testing.T) {
+	// Numbers are decoded like names
+	var decoded Triggers
+	data := `[{"type":3,"weeksInterval":1,"daysOfWeek":[1,"Tuesday"]},{"type":"Monthly","monthsOfYear":[12]}]`
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	want := Triggers{
+		WeeklyTrigger{WeeksInterval: 1, DaysOfWeek: Weekdays{time.Monday, time.Tuesday}},
+		MonthlyTrigger{MonthsOfYear: Months{time.December}},
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("decoded %#v, want %#v", decoded, want)
+	}
+	for _, data := range []string{
+		`[{"enabled":true}]`,
+		`[{"type":"Hourly"}]`,
+		`[{"type":"Custom"}]`,
+		`[{"type":"Weekly","daysOfWeek":["Someday"]}]`,
+	} {
+		if err := json.Unmarshal([]byte(data), &decoded); err == nil {
+			t.Errorf("decoded %s without error", data)
+		}
+	}
+}
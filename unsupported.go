@@ -0,0 +1,204 @@
+//go:build !windows
+
+package taskscheduler
+
+import (
+	"context"
+	"time"
+)
+
+// session is the connection of a Scheduler, it can not be established on this platform
+type session struct{}
+
+// handle is the object of a TaskHandle, it can not be obtained on this platform
+type handle struct{}
+
+// instance is the object of a RunningTask, it can not be obtained on this platform
+type instance struct{}
+
+func hresult(err error) uint32 {
+	return 0
+}
+
+// Connect returns ErrUnsupportedPlatform
+func (s *Scheduler) Connect() error { return ErrUnsupportedPlatform }
+
+// Close returns nil, a Scheduler can not be connected on this platform
+func (s *Scheduler) Close() error { return nil }
+
+// GetTasks returns ErrUnsupportedPlatform
+func (s *Scheduler) GetTasks() ([]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// GetTask returns ErrUnsupportedPlatform
+func (s *Scheduler) GetTask(path string) (Task, error) { return Task{}, ErrUnsupportedPlatform }
+
+// GetTaskHandles returns ErrUnsupportedPlatform
+func (s *Scheduler) GetTaskHandles() ([]*TaskHandle, error) { return nil, ErrUnsupportedPlatform }
+
+// GetTaskHandle returns ErrUnsupportedPlatform
+func (s *Scheduler) GetTaskHandle(path string) (*TaskHandle, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// Enable returns ErrUnsupportedPlatform
+func (h *TaskHandle) Enable() error { return ErrUnsupportedPlatform }
+
+// Disable returns ErrUnsupportedPlatform
+func (h *TaskHandle) Disable() error { return ErrUnsupportedPlatform }
+
+// Run returns ErrUnsupportedPlatform
+func (h *TaskHandle) Run(args []string) (RunningTask, error) {
+	return RunningTask{}, ErrUnsupportedPlatform
+}
+
+// Stop returns ErrUnsupportedPlatform
+func (h *TaskHandle) Stop() error { return ErrUnsupportedPlatform }
+
+// Release does nothing on this platform
+func (h *TaskHandle) Release() {}
+
+// GetTasks returns ErrUnsupportedPlatform
+func GetTasks() ([]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// GetTasksContext returns ErrUnsupportedPlatform
+func GetTasksContext(ctx context.Context) ([]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// GetTasksWithErrors returns ErrUnsupportedPlatform
+func GetTasksWithErrors() ([]Task, []error) { return nil, []error{ErrUnsupportedPlatform} }
+
+// GetTasksFilter returns ErrUnsupportedPlatform
+func GetTasksFilter(pred func(Task) bool) ([]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// WalkTasks returns ErrUnsupportedPlatform
+func WalkTasks(fn func(Task) error) error { return ErrUnsupportedPlatform }
+
+// GetTasksInFolder returns ErrUnsupportedPlatform
+func GetTasksInFolder(path string) ([]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// GetTasksShallow returns ErrUnsupportedPlatform
+func GetTasksShallow(path string) ([]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// GetTasksIncludingHidden returns ErrUnsupportedPlatform
+func GetTasksIncludingHidden() ([]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// GetTasksMetadataOnly returns ErrUnsupportedPlatform
+func GetTasksMetadataOnly() ([]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// GetTasksRemote returns ErrUnsupportedPlatform
+func GetTasksRemote(server, user, domain, password string) ([]Task, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// GetTasksConcurrent returns ErrUnsupportedPlatform
+func GetTasksConcurrent(limit int) ([]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// GetTask returns ErrUnsupportedPlatform
+func GetTask(path string) (Task, error) { return Task{}, ErrUnsupportedPlatform }
+
+// TaskExists returns ErrUnsupportedPlatform
+func TaskExists(path string) (bool, error) { return false, ErrUnsupportedPlatform }
+
+// GetTaskBatch returns ErrUnsupportedPlatform
+func GetTaskBatch(paths []string) (map[string]Task, error) { return nil, ErrUnsupportedPlatform }
+
+// DeleteTask returns ErrUnsupportedPlatform
+func DeleteTask(path string) error { return ErrUnsupportedPlatform }
+
+// EnableTask returns ErrUnsupportedPlatform
+func EnableTask(path string) error { return ErrUnsupportedPlatform }
+
+// DisableTask returns ErrUnsupportedPlatform
+func DisableTask(path string) error { return ErrUnsupportedPlatform }
+
+// SetEnabledInFolder returns ErrUnsupportedPlatform
+func SetEnabledInFolder(path string, enabled bool) (int, error) { return 0, ErrUnsupportedPlatform }
+
+// SetEnabledInFolderShallow returns ErrUnsupportedPlatform
+func SetEnabledInFolderShallow(path string, enabled bool) (int, error) {
+	return 0, ErrUnsupportedPlatform
+}
+
+// RegisterTask returns ErrUnsupportedPlatform
+func RegisterTask(path string, def TaskDefinition) (Task, error) {
+	return Task{}, ErrUnsupportedPlatform
+}
+
+// ValidateDefinition returns ErrUnsupportedPlatform
+func ValidateDefinition(def TaskDefinition) error { return ErrUnsupportedPlatform }
+
+// UpdateTask returns ErrUnsupportedPlatform
+func UpdateTask(path string, def TaskDefinition) error { return ErrUnsupportedPlatform }
+
+// ExportTaskXML returns ErrUnsupportedPlatform
+func ExportTaskXML(path string) (string, error) { return "", ErrUnsupportedPlatform }
+
+// ExportAllTasksXML returns ErrUnsupportedPlatform
+func ExportAllTasksXML() (map[string]string, error) { return nil, ErrUnsupportedPlatform }
+
+// ImportTaskFromXML returns ErrUnsupportedPlatform
+func ImportTaskFromXML(path, xml string) (Task, error) { return Task{}, ErrUnsupportedPlatform }
+
+func importTaskFromXML(path, xml string, overwrite, createMissingFolder bool) (Task, error) {
+	return Task{}, ErrUnsupportedPlatform
+}
+
+// GetTaskSDDL returns ErrUnsupportedPlatform
+func GetTaskSDDL(path string) (string, error) { return "", ErrUnsupportedPlatform }
+
+// SetTaskSDDL returns ErrUnsupportedPlatform
+func SetTaskSDDL(path, sddl string) error { return ErrUnsupportedPlatform }
+
+// GetFolders returns ErrUnsupportedPlatform
+func GetFolders() ([]string, error) { return nil, ErrUnsupportedPlatform }
+
+// CreateFolder returns ErrUnsupportedPlatform
+func CreateFolder(path string) error { return ErrUnsupportedPlatform }
+
+// DeleteFolder returns ErrUnsupportedPlatform
+func DeleteFolder(path string, force bool) error { return ErrUnsupportedPlatform }
+
+// GetRunningTasks returns ErrUnsupportedPlatform
+func GetRunningTasks() ([]RunningTask, error) { return nil, ErrUnsupportedPlatform }
+
+// GetRunningTasks returns ErrUnsupportedPlatform
+func (s *Scheduler) GetRunningTasks() ([]RunningTask, error) { return nil, ErrUnsupportedPlatform }
+
+// Release does nothing on this platform
+func (r *RunningTask) Release() {}
+
+// CurrentAction returns ErrUnsupportedPlatform
+func (r *RunningTask) CurrentAction() (string, error) { return "", ErrUnsupportedPlatform }
+
+// CurrentState returns ErrUnsupportedPlatform
+func (r *RunningTask) CurrentState() (TaskState, error) {
+	return TaskStateUnknown, ErrUnsupportedPlatform
+}
+
+// EnginePID returns ErrUnsupportedPlatform
+func (r *RunningTask) EnginePID() (uint32, error) { return 0, ErrUnsupportedPlatform }
+
+// RunTask returns ErrUnsupportedPlatform
+func RunTask(path string, args []string) (RunningTask, error) {
+	return RunningTask{}, ErrUnsupportedPlatform
+}
+
+// RunTaskAndWatch returns ErrUnsupportedPlatform
+func RunTaskAndWatch(ctx context.Context, path string) (<-chan TaskState, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// StopTask returns ErrUnsupportedPlatform
+func StopTask(path string) error { return ErrUnsupportedPlatform }
+
+// IsTaskRunning returns ErrUnsupportedPlatform
+func IsTaskRunning(path string) (bool, error) { return false, ErrUnsupportedPlatform }
+
+// WaitForTask returns ErrUnsupportedPlatform
+func WaitForTask(path string, timeout time.Duration) (uint32, error) {
+	return 0, ErrUnsupportedPlatform
+}
+
+// GetTaskHistory returns ErrUnsupportedPlatform
+func GetTaskHistory(path string, since time.Time) ([]HistoryEvent, error) {
+	return nil, ErrUnsupportedPlatform
+}
@@ -0,0 +1,224 @@
+package taskscheduler
+
+import (
+	"context"
+	"fmt"
+)
+
+// taskEnumHidden is the TASK_ENUM_HIDDEN flag of ITaskFolder::GetTasks
+const taskEnumHidden = 0x1
+
+// walker walks the folder tree of Windows Task Scheduler 2.0 and collects the failures
+// of single folders and tasks instead of aborting the whole enumeration.
+type walker struct {
+	ctx     context.Context
+	shallow bool // do not walk into subfolders
+	hidden  bool // include hidden tasks
+	// metadataOnly only reads the cheap properties of the tasks, not their definitions
+	metadataOnly bool
+	// foldersOnly only walks the folders, their tasks are not enumerated
+	foldersOnly bool
+	// onFolder is called with the path of every folder before its subfolders are walked, if set
+	onFolder func(path string)
+	// onTask is called for every task instead of collecting it, if set. The walk is aborted
+	// if it returns an error. task is released after onTask returned.
+	onTask func(task dispatcher, t Task) error
+	// onError is called for every failure of the folder path instead of collecting it in errs, if set
+	onError func(path string, err error)
+	errs    []error
+}
+
+// fail records a failure of the folder path of the walk
+func (w *walker) fail(path string, err error) {
+	if w.onError != nil {
+		w.onError(path, err)
+		return
+	}
+	w.errs = append(w.errs, err)
+}
+
+func (w *walker) getTasksRecursively(folder dispatcher) ([]Task, error) {
+	var (
+		tasks []Task
+		err   error
+	)
+	path, _ := getValue(folder, "path", nil).(string)
+	if w.onFolder != nil {
+		w.onFolder(path)
+	}
+	// Get Tasks in subfolders first, unless only the folder itself is enumerated
+	if !w.shallow {
+		folderIterator, err := callObject(folder, "GetFolders", int32(0))
+		if err != nil {
+			w.fail(path, &COMError{Op: "get subfolders of folder " + path, Err: err})
+			return tasks, nil
+		}
+		value, err := folderIterator.GetProperty("count")
+		if err != nil {
+			folderIterator.Release()
+			w.fail(path, &COMError{Op: "count subfolders of folder " + path, Err: err})
+			return tasks, nil
+		}
+		count := toInt(value)
+		for i := 1; i <= count; i++ {
+			if err = w.ctx.Err(); err != nil {
+				folderIterator.Release()
+				return tasks, err
+			}
+			// Get Tasks of subfolder i
+			subfolder, err := getObject(folderIterator, "item", int32(i))
+			if err != nil {
+				w.fail(path, &COMError{Op: fmt.Sprintf("get subfolder %d of folder %s", i, path), Err: err})
+				continue
+			}
+			subtasks, err := w.getTasksRecursively(subfolder)
+			tasks = append(tasks, subtasks...)
+			subfolder.Release()
+			if err != nil {
+				folderIterator.Release()
+				return tasks, err
+			}
+		}
+		folderIterator.Release()
+	}
+	if w.foldersOnly {
+		return tasks, nil
+	}
+	// Get Tasks
+	flags := int32(0)
+	if w.hidden {
+		flags = taskEnumHidden
+	}
+	taskIterator, err := callObject(folder, "GetTasks", flags)
+	if err != nil {
+		w.fail(path, &COMError{Op: "get tasks of folder " + path, Err: err})
+		return tasks, nil
+	}
+	value, err := taskIterator.GetProperty("count")
+	if err != nil {
+		taskIterator.Release()
+		w.fail(path, &COMError{Op: "count tasks of folder " + path, Err: err})
+		return tasks, nil
+	}
+	count := toInt(value)
+	for i := 1; i <= count; i++ {
+		if err = w.ctx.Err(); err != nil {
+			taskIterator.Release()
+			return tasks, err
+		}
+		// Get Task i
+		task, err := getObject(taskIterator, "item", int32(i))
+		if err != nil {
+			w.fail(path, &COMError{Op: fmt.Sprintf("get task %d of folder %s", i, path), Err: err})
+			continue
+		}
+		t := w.parseTask(task)
+		if w.onTask != nil {
+			err = w.onTask(task, t)
+		} else {
+			tasks = append(tasks, t)
+		}
+		task.Release()
+		if err != nil {
+			taskIterator.Release()
+			return tasks, err
+		}
+	}
+	taskIterator.Release()
+	return tasks, nil
+}
+
+// parseTask reads a task of the walk, see walker.metadataOnly
+func (w *walker) parseTask(task dispatcher) Task {
+	if w.metadataOnly {
+		return parseTaskMetadata(task)
+	}
+	return parseTask(task)
+}
+
+// parseTask reads the properties of an IRegisteredTask object into a Task.
+func parseTask(task dispatcher) (t Task) {
+	t = parseTaskMetadata(task)
+	// Get more details, e.g. actions, triggers, principal, registration info and settings
+	definition, err := getObject(task, "definition")
+	if err != nil {
+		t.warn("definition", err)
+		return
+	}
+	defer definition.Release()
+	t.definitionRead = true
+	if value, err := definition.GetProperty("xmlText"); err == nil {
+		t.xml, _ = value.(string)
+	} else {
+		t.warn("xmlText", err)
+	}
+	t.ID = taskID(t.xml)
+	t.RegistrationInfo = parseRegistrationInfo(definition, t.warn)
+	t.Settings = parseSettings(definition, t.warn)
+	t.Hidden = t.Settings.Hidden
+	t.TriggerList = parseTriggers(definition, t.warn)
+	t.Principal = parsePrincipal(definition, t.warn)
+	t.AllActions = parseActions(definition, t.warn)
+	for _, a := range t.AllActions {
+		if a, ok := a.(ExecAction); ok {
+			t.ActionList = append(t.ActionList, a)
+		}
+	}
+	return
+}
+
+// parseTaskMetadata reads the properties of an IRegisteredTask object into a Task without
+// reading its definition.
+func parseTaskMetadata(task dispatcher) (t Task) {
+	var (
+		value interface{}
+		err   error
+	)
+	if value, err = task.GetProperty("name"); err == nil {
+		t.Name, _ = value.(string)
+	} else {
+		t.warn("name", err)
+	}
+	if value, err = task.GetProperty("path"); err == nil {
+		t.Path, _ = value.(string)
+	} else {
+		t.warn("path", err)
+	}
+	if value, err = task.GetProperty("enabled"); err == nil {
+		t.Enabled, _ = value.(bool)
+	} else {
+		t.warn("enabled", err)
+	}
+	if value, err = task.GetProperty("state"); err == nil {
+		t.State = TaskState(toInt(value))
+	} else {
+		t.warn("state", err)
+	}
+	if value, err = task.GetProperty("lastRunTime"); err == nil {
+		t.LastRunTime = toTime(value)
+	} else {
+		t.warn("lastRunTime", err)
+	}
+	if value, err = task.GetProperty("nextRunTime"); err == nil {
+		t.NextRunTime = toTime(value)
+	} else {
+		t.warn("nextRunTime", err)
+	}
+	if value, err = task.GetProperty("lastTaskResult"); err == nil {
+		t.LastTaskResult = uint32(toInt(value))
+	} else {
+		t.warn("lastTaskResult", err)
+	}
+	if value, err = task.GetProperty("numberOfMissedRuns"); err == nil {
+		t.NumberOfMissedRuns = toInt(value)
+	} else {
+		t.warn("numberOfMissedRuns", err)
+	}
+	if instances, err := callObject(task, "GetInstances", int32(0)); err == nil {
+		t.RunningInstances = getInt(instances, "count", warnings(t.warn).nested("instances"))
+		instances.Release()
+	} else {
+		t.warn("instances", err)
+	}
+	return
+}
@@ -0,0 +1,304 @@
+package taskscheduler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// fakeTree is a fake Task Scheduler with tasks in the root folder and in nested subfolders
+var fakeTree = fakeFolder{
+	path:  "\\",
+	tasks: []string{"A"},
+	folders: []fakeFolder{
+		{
+			path:    "\\Sub",
+			tasks:   []string{"B1", "B2"},
+			hidden:  []string{"Hidden"},
+			folders: []fakeFolder{{path: "\\Sub\\Deep", tasks: []string{"C"}}},
+		},
+		{path: "\\Empty"},
+	},
+}
+
+// walk walks root with w and returns the paths of the found tasks
+func walk(t *testing.T, com *fakeCOM, w *walker, root *fakeObject) ([]string, error) {
+	t.Helper()
+	if w.ctx == nil {
+		w.ctx = context.Background()
+	}
+	com.acquire(root)
+	tasks, err := w.getTasksRecursively(root)
+	root.Release()
+	com.checkReleased()
+	var paths []string
+	for _, task := range tasks {
+		paths = append(paths, task.Path)
+	}
+	return paths, err
+}
+
+func TestWalkerRecursive(t *testing.T) {
+	com := &fakeCOM{t: t}
+	w := &walker{}
+	paths, err := walk(t, com, w, com.folder(fakeTree))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tasks of subfolders are returned before the tasks of their folder
+	want := []string{"\\Sub\\Deep\\C", "\\Sub\\B1", "\\Sub\\B2", "\\A"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("got tasks %v, want %v", paths, want)
+	}
+	if len(w.errs) > 0 {
+		t.Errorf("got errors %v", w.errs)
+	}
+}
+
+func TestWalkerShallow(t *testing.T) {
+	com := &fakeCOM{t: t}
+	paths, err := walk(t, com, &walker{shallow: true}, com.folder(fakeTree.folders[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"\\Sub\\B1", "\\Sub\\B2"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("got tasks %v, want %v", paths, want)
+	}
+}
+
+func TestWalkerHidden(t *testing.T) {
+	com := &fakeCOM{t: t}
+	paths, err := walk(t, com, &walker{hidden: true}, com.folder(fakeTree))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"\\Sub\\Deep\\C", "\\Sub\\B1", "\\Sub\\B2", "\\Sub\\Hidden", "\\A"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("got tasks %v, want %v", paths, want)
+	}
+}
+
+func TestWalkerCancel(t *testing.T) {
+	com := &fakeCOM{t: t}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var visited []string
+	w := &walker{ctx: ctx, onTask: func(_ dispatcher, task Task) error {
+		visited = append(visited, task.Path)
+		cancel()
+		return nil
+	}}
+	if _, err := walk(t, com, w, com.folder(fakeTree)); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if want := []string{"\\Sub\\Deep\\C"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("visited tasks %v, want %v", visited, want)
+	}
+}
+
+func TestWalkerErrors(t *testing.T) {
+	com := &fakeCOM{t: t}
+	root := com.folder(fakeTree)
+	// The tasks of \Sub can not be enumerated and the second subfolder of the root can not be read
+	sub := com.folder(fakeTree.folders[0])
+	sub.members["GetTasks"] = errFake
+	root.members["GetFolders"] = com.collection("subfolders of \\", sub, errFake)
+	w := &walker{}
+	paths, err := walk(t, com, w, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"\\Sub\\Deep\\C", "\\A"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("got tasks %v, want %v", paths, want)
+	}
+	if len(w.errs) != 2 {
+		t.Fatalf("got errors %v, want 2 errors", w.errs)
+	}
+	for _, err := range w.errs {
+		if comErr, ok := err.(*COMError); !ok || comErr.Err != errFake {
+			t.Errorf("got error %v, want COMError of the fake failure", err)
+		}
+	}
+}
+
+func TestWalkerReleases(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(com *fakeCOM, root *fakeObject)
+		fail  bool // onTask fails for the second task of \Sub
+	}{
+		{name: "complete walk"},
+		{name: "subfolders fail", setup: func(com *fakeCOM, root *fakeObject) {
+			root.members["GetFolders"] = errFake
+		}},
+		{name: "subfolders can not be counted", setup: func(com *fakeCOM, root *fakeObject) {
+			root.members["GetFolders"] = com.object("subfolders", map[string]interface{}{"count": errFake})
+		}},
+		{name: "subfolder fails", setup: func(com *fakeCOM, root *fakeObject) {
+			root.members["GetFolders"] = com.collection("subfolders", errFake, com.folder(fakeTree.folders[0]))
+		}},
+		{name: "tasks fail", setup: func(com *fakeCOM, root *fakeObject) {
+			root.members["GetTasks"] = errFake
+		}},
+		{name: "tasks can not be counted", setup: func(com *fakeCOM, root *fakeObject) {
+			root.members["GetTasks"] = com.object("tasks", map[string]interface{}{"count": errFake})
+		}},
+		{name: "task fails", setup: func(com *fakeCOM, root *fakeObject) {
+			root.members["GetTasks"] = com.collection("tasks", errFake, com.task("\\A"))
+		}},
+		{name: "definition is parsed", setup: func(com *fakeCOM, root *fakeObject) {
+			root.members["GetTasks"] = com.collection("tasks", fakeTaskWithDefinition(com, "\\A"))
+		}},
+		{name: "onTask fails in the middle of a folder", fail: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			com := &fakeCOM{t: t}
+			root := com.folder(fakeTree)
+			if test.setup != nil {
+				test.setup(com, root)
+			}
+			w := &walker{}
+			if test.fail {
+				w.onTask = func(_ dispatcher, task Task) error {
+					if task.Path == "\\Sub\\B2" {
+						return errFake
+					}
+					return nil
+				}
+			}
+			// walk checks that all references are released
+			if _, err := walk(t, com, w, root); (err != nil) != test.fail {
+				t.Errorf("got error %v", err)
+			}
+		})
+	}
+}
+
+// fakeTaskWithDefinition returns a fake IRegisteredTask object at path with a definition
+// that has an object for every part that is parsed
+func fakeTaskWithDefinition(com *fakeCOM, path string) *fakeObject {
+	task := com.task(path)
+	task.members["definition"] = com.object("definition of "+path, map[string]interface{}{
+		"xmlText":          "<Task/>",
+		"registrationInfo": com.object("registration info", map[string]interface{}{"author": "Author"}),
+		"settings": com.object("settings", map[string]interface{}{
+			"enabled":         true,
+			"idleSettings":    com.object("idle settings", map[string]interface{}{}),
+			"networkSettings": com.object("network settings", map[string]interface{}{}),
+		}),
+		"triggers": com.collection("triggers", com.object("trigger", map[string]interface{}{
+			"type":         int32(TriggerTypeEvent),
+			"repetition":   com.object("repetition", map[string]interface{}{}),
+			"valueQueries": com.collection("value queries", com.object("pair", map[string]interface{}{"name": "n", "value": "v"})),
+		})),
+		"principal": com.object("principal", map[string]interface{}{"userId": "SYSTEM"}),
+		"actions": com.collection("actions",
+			com.object("exec action", map[string]interface{}{"type": int32(ActionTypeExec), "path": "cmd.exe"}),
+			com.object("raw action", map[string]interface{}{"type": int32(99), "id": "Raw", "path": com.object("object", nil)}),
+		),
+	})
+	return task
+}
+
+func TestParseTaskWarnings(t *testing.T) {
+	com := &fakeCOM{t: t}
+	task := com.task("\\A")
+	// The path of the exec action and the user of the principal can not be read
+	task.members["definition"] = com.object("definition", map[string]interface{}{
+		"xmlText": "<Task/>",
+		"actions": com.collection("actions", com.object("exec action", map[string]interface{}{
+			"type": int32(ActionTypeExec),
+			"path": errFake,
+		})),
+		"principal": com.object("principal", map[string]interface{}{"userId": errFake}),
+	})
+	com.acquire(task)
+	parsed := parseTask(task)
+	task.Release()
+	com.checkReleased()
+	for _, want := range []string{
+		"Could not read actions[1].path: fake COM failure",
+		"Could not read principal.userId: fake COM failure",
+	} {
+		found := false
+		for _, warning := range parsed.ParseWarnings {
+			found = found || warning == want
+		}
+		if !found {
+			t.Errorf("got warnings %q, want %q", parsed.ParseWarnings, want)
+		}
+	}
+}
+
+func TestEffectivelyEnabled(t *testing.T) {
+	com := &fakeCOM{t: t}
+	// The settings disable the task, but the XML of its definition can not be read
+	task := com.task("\\A")
+	task.members["definition"] = com.object("definition", map[string]interface{}{
+		"xmlText":  errFake,
+		"settings": com.object("settings", map[string]interface{}{"enabled": false}),
+	})
+	com.acquire(task)
+	parsed, metadata := parseTask(task), parseTaskMetadata(task)
+	task.Release()
+	com.checkReleased()
+	if parsed.EffectivelyEnabled() {
+		t.Error("Task with disabled settings is effectively enabled")
+	}
+	if !metadata.EffectivelyEnabled() {
+		t.Error("Task without definition is not effectively enabled")
+	}
+	if !(Task{Enabled: true}).EffectivelyEnabled() {
+		t.Error("Task that was not read from the Task Scheduler is not effectively enabled")
+	}
+}
+
+func TestWalkerOnError(t *testing.T) {
+	com := &fakeCOM{t: t}
+	root := com.folder(fakeTree)
+	sub := com.folder(fakeTree.folders[0])
+	sub.members["GetTasks"] = errFake
+	root.members["GetFolders"] = com.collection("subfolders of \\", sub)
+	failed := map[string]error{}
+	w := &walker{onError: func(path string, err error) { failed[path] = err }}
+	if _, err := walk(t, com, w, root); err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed["\\Sub"] == nil {
+		t.Errorf("got failures %v, want a failure of \\Sub", failed)
+	}
+	if len(w.errs) > 0 {
+		t.Errorf("got errors %v, want them to be passed to onError", w.errs)
+	}
+}
+
+func TestWalkerFolders(t *testing.T) {
+	com := &fakeCOM{t: t}
+	root := com.folder(fakeTree)
+	// The tasks are not enumerated, so failing GetTasks must not be reported
+	root.members["GetTasks"] = errFake
+	sub := com.folder(fakeTree.folders[0])
+	sub.members["GetFolders"] = errFake
+	root.members["GetFolders"] = com.collection("subfolders of \\", sub, com.folder(fakeTree.folders[1]))
+	var folders []string
+	failed := BatchError{}
+	w := &walker{
+		foldersOnly: true,
+		onFolder:    func(path string) { folders = append(folders, path) },
+		onError:     failed.add,
+	}
+	paths, err := walk(t, com, w, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) > 0 {
+		t.Errorf("got tasks %v, want none", paths)
+	}
+	if want := []string{"\\", "\\Sub", "\\Empty"}; !reflect.DeepEqual(folders, want) {
+		t.Errorf("got folders %v, want %v", folders, want)
+	}
+	if len(failed) != 1 || failed["\\Sub"] == nil {
+		t.Errorf("got failures %v, want a failure of \\Sub", failed)
+	}
+}
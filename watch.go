@@ -0,0 +1,121 @@
+package taskscheduler
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// WatchInterval is the interval in which WatchTasks compares snapshots of the scheduled Tasks
+var WatchInterval = 10 * time.Second
+
+// TaskEventType is the type of a TaskEvent
+type TaskEventType int
+
+// Types of a TaskEvent
+const (
+	TaskEventCreated TaskEventType = iota // a task was registered
+	TaskEventDeleted                      // a task was deleted
+	TaskEventRun                          // a task was run
+)
+
+// String returns the name of the event type, e.g. "Created"
+func (t TaskEventType) String() string {
+	switch t {
+	case TaskEventCreated:
+		return "Created"
+	case TaskEventDeleted:
+		return "Deleted"
+	case TaskEventRun:
+		return "Run"
+	}
+	return "Unknown"
+}
+
+// MarshalJSON encodes the event type as its name, e.g. "Created"
+func (t TaskEventType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes the event type from its name or number
+func (t *TaskEventType) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "event type", int(TaskEventRun), func(i int) string { return TaskEventType(i).String() })
+	*t = TaskEventType(n)
+	return err
+}
+
+// TaskEvent is a change of a scheduled Task detected by WatchTasks
+type TaskEvent struct {
+	Type TaskEventType `json:"type"`
+	Path string        `json:"path"`
+	Time time.Time     `json:"time"` // time of the run for TaskEventRun, time of detection otherwise
+}
+
+// WatchTasks sends events on the returned channel whenever a Task is created, deleted or run
+// until ctx is done, then the channel is closed. Changes are detected by comparing snapshots
+// of all Tasks every WatchInterval, so a Task that runs multiple times in between results in
+// a single event. An error is returned if the first snapshot can not be taken.
+func WatchTasks(ctx context.Context) (<-chan TaskEvent, error) {
+	tasks, err := GetTasksContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan TaskEvent)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(WatchInterval)
+		defer ticker.Stop()
+		previous := tasksByPath(tasks)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			tasks, err := GetTasksContext(ctx)
+			if err != nil {
+				// Keep the previous snapshot and try again in the next interval
+				continue
+			}
+			current := tasksByPath(tasks)
+			for _, event := range diffSnapshots(previous, current, time.Now()) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			previous = current
+		}
+	}()
+	return events, nil
+}
+
+func tasksByPath(tasks []Task) map[string]Task {
+	m := make(map[string]Task, len(tasks))
+	for _, t := range tasks {
+		m[t.Path] = t
+	}
+	return m
+}
+
+// diffSnapshots returns the events between two snapshots sorted by path.
+func diffSnapshots(previous, current map[string]Task, now time.Time) (events []TaskEvent) {
+	for path, t := range current {
+		old, ok := previous[path]
+		switch {
+		case !ok:
+			events = append(events, TaskEvent{Type: TaskEventCreated, Path: path, Time: now})
+		case t.LastRunTime.After(old.LastRunTime):
+			events = append(events, TaskEvent{Type: TaskEventRun, Path: path, Time: t.LastRunTime})
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			events = append(events, TaskEvent{Type: TaskEventDeleted, Path: path, Time: now})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return
+}
@@ -0,0 +1,34 @@
+package taskscheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	run := now.Add(-time.Minute)
+	previous := tasksByPath([]Task{
+		{Path: "\\Idle", LastRunTime: run},
+		{Path: "\\Run", LastRunTime: run.Add(-time.Hour)},
+		{Path: "\\Deleted"},
+	})
+	current := tasksByPath([]Task{
+		{Path: "\\Idle", LastRunTime: run},
+		{Path: "\\Run", LastRunTime: run},
+		{Path: "\\Created", LastRunTime: run},
+	})
+	// A created task is not reported as run, even if it has run before it was detected
+	want := []TaskEvent{
+		{Type: TaskEventCreated, Path: "\\Created", Time: now},
+		{Type: TaskEventDeleted, Path: "\\Deleted", Time: now},
+		{Type: TaskEventRun, Path: "\\Run", Time: run},
+	}
+	if events := diffSnapshots(previous, current, now); !reflect.DeepEqual(events, want) {
+		t.Errorf("got events %v, want %v", events, want)
+	}
+	if events := diffSnapshots(current, current, now); events != nil {
+		t.Errorf("got events %v of equal snapshots, want none", events)
+	}
+}
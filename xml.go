@@ -0,0 +1,123 @@
+package taskscheduler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// ImportTasksFromDirectory registers a Task for every .xml file in dir and its subdirectories,
+// e.g. written from the result of ExportAllTasksXML. The path of a Task is derived from the
+// path of its file relative to dir, e.g. "MyApp/MyTask.xml" is registered as "\\MyApp\\MyTask",
+// and missing folders are created. Existing Tasks are overwritten, see ImportNewTasksFromDirectory
+// to keep them. The Tasks log on like the principals in their XML, see ImportTaskFromXML. A failing
+// file does not abort the import, the errors of all failed files are returned.
+func ImportTasksFromDirectory(dir string) ([]Task, []error) {
+	return importTasksFromDirectory(dir, true)
+}
+
+// ImportNewTasksFromDirectory is like ImportTasksFromDirectory but keeps existing Tasks,
+// ErrTaskExists is returned for their files.
+func ImportNewTasksFromDirectory(dir string) ([]Task, []error) {
+	return importTasksFromDirectory(dir, false)
+}
+
+func importTasksFromDirectory(dir string, overwrite bool) (tasks []Task, errs []error) {
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if info.IsDir() || !strings.EqualFold(filepath.Ext(file), ".xml") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		task, err := importTaskFromFile(taskPathOfFile(rel), file, overwrite)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Could not import %s: %w", file, err))
+			return nil
+		}
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return
+}
+
+// taskPathOfFile returns the path of the Task of the XML file at rel, relative to the directory
+// of an import, e.g. "\\MyApp\\MyTask" for "MyApp/MyTask.xml"
+func taskPathOfFile(rel string) string {
+	rel = strings.TrimSuffix(filepath.ToSlash(rel), filepath.Ext(rel))
+	return "\\" + strings.Replace(rel, "/", "\\", -1)
+}
+
+// importTaskFromFile registers the Task at path from the XML in file, its folder is created if
+// it is missing.
+func importTaskFromFile(path, file string, overwrite bool) (Task, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return Task{}, err
+	}
+	return importTaskFromXML(path, decodeXML(b), overwrite, true)
+}
+
+// decodeXML decodes the content of a XML file, which is UTF-16 if exported by the Task
+// Scheduler or schtasks.exe and UTF-8 otherwise.
+func decodeXML(b []byte) string {
+	var bigEndian bool
+	switch {
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE}):
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		bigEndian = true
+	default:
+		return string(bytes.TrimPrefix(b, []byte{0xEF, 0xBB, 0xBF}))
+	}
+	b = b[2:]
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		if bigEndian {
+			u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+		} else {
+			u[i] = uint16(b[2*i]) | uint16(b[2*i+1])<<8
+		}
+	}
+	return string(utf16.Decode(u))
+}
+
+// taskXML is the principal of the XML of a task definition
+type taskXML struct {
+	LogonType string `xml:"Principals>Principal>LogonType"`
+	GroupID   string `xml:"Principals>Principal>GroupId"`
+}
+
+// xmlLogonType returns the logon type of the principal in the XML of a task definition.
+// LogonTypeNone is returned if the XML does not define it, so the principal is used as is.
+func xmlLogonType(s string) LogonType {
+	var t taskXML
+	decoder := xml.NewDecoder(strings.NewReader(s))
+	// The XML is already decoded, even if it declares e.g. UTF-16
+	decoder.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+	if err := decoder.Decode(&t); err != nil {
+		return LogonTypeNone
+	}
+	for l := LogonTypePassword; l <= LogonTypeInteractiveTokenOrPassword; l++ {
+		if strings.EqualFold(l.String(), strings.TrimSpace(t.LogonType)) {
+			return l
+		}
+	}
+	if t.GroupID != "" {
+		return LogonTypeGroup
+	}
+	return LogonTypeNone
+}
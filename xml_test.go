@@ -0,0 +1,72 @@
+package taskscheduler
+
+import (
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestXMLLogonType(t *testing.T) {
+	tests := []struct {
+		xml  string
+		want LogonType
+	}{
+		{xml: taskXMLWithPrincipal(`<UserId>S-1-5-18</UserId><RunLevel>HighestAvailable</RunLevel>`), want: LogonTypeNone},
+		{xml: taskXMLWithPrincipal(`<UserId>DOMAIN\svc</UserId><LogonType>S4U</LogonType>`), want: LogonTypeS4U},
+		{xml: taskXMLWithPrincipal(`<UserId>DOMAIN\user</UserId><LogonType>Password</LogonType>`), want: LogonTypePassword},
+		{xml: taskXMLWithPrincipal(`<LogonType>InteractiveToken</LogonType>`), want: LogonTypeInteractiveToken},
+		{xml: taskXMLWithPrincipal(`<GroupId>S-1-5-32-545</GroupId>`), want: LogonTypeGroup},
+		{xml: "<Task", want: LogonTypeNone},
+	}
+	for _, test := range tests {
+		if got := xmlLogonType(test.xml); got != test.want {
+			t.Errorf("xmlLogonType(%q) = %v, want %v", test.xml, got, test.want)
+		}
+	}
+}
+
+// taskXMLWithPrincipal returns the XML of a task definition as exported by the Task Scheduler
+// with the given principal
+func taskXMLWithPrincipal(principal string) string {
+	return `<?xml version="1.0" encoding="UTF-16"?>
+<Task version="1.2" xmlns="http://schemas.microsoft.com/windows/2004/02/mit/task">
+  <RegistrationInfo><URI>\MyTask</URI></RegistrationInfo>
+  <Principals><Principal id="Author">` + principal + `</Principal></Principals>
+  <Actions Context="Author"><Exec><Command>cmd.exe</Command></Exec></Actions>
+</Task>`
+}
+
+func TestTaskPathOfFile(t *testing.T) {
+	tests := map[string]string{
+		"MyTask.xml":           "\\MyTask",
+		"MyApp/MyTask.xml":     "\\MyApp\\MyTask",
+		"MyApp/Jobs/Daily.XML": "\\MyApp\\Jobs\\Daily",
+		"My.App/My.Task.xml":   "\\My.App\\My.Task",
+	}
+	for rel, want := range tests {
+		if got := taskPathOfFile(filepath.FromSlash(rel)); got != want {
+			t.Errorf("taskPathOfFile(%q) = %q, want %q", rel, got, want)
+		}
+	}
+}
+
+func TestDecodeXML(t *testing.T) {
+	const want = `<?xml version="1.0" encoding="UTF-16"?><Task>Ä</Task>`
+	units := utf16.Encode([]rune(want))
+	littleEndian, bigEndian := []byte{0xFF, 0xFE}, []byte{0xFE, 0xFF}
+	for _, u := range units {
+		littleEndian = append(littleEndian, byte(u), byte(u>>8))
+		bigEndian = append(bigEndian, byte(u>>8), byte(u))
+	}
+	tests := map[string][]byte{
+		"UTF-16LE":        littleEndian,
+		"UTF-16BE":        bigEndian,
+		"UTF-8 with BOM":  append([]byte{0xEF, 0xBB, 0xBF}, want...),
+		"UTF-8 w/out BOM": []byte(want),
+	}
+	for name, b := range tests {
+		if got := decodeXML(b); got != want {
+			t.Errorf("decodeXML of %s = %q, want %q", name, got, want)
+		}
+	}
+}
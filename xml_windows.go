@@ -0,0 +1,125 @@
+package taskscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// ExportTaskXML returns the XML of the definition of the Task at path. The XML follows
+// the Task Scheduler schema and can be imported again.
+func ExportTaskXML(path string) (xml string, err error) {
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		registered, err := getRegisteredTask(ts, path)
+		if err != nil {
+			return err
+		}
+		defer registered.Release()
+		xml, err = definitionXML(comObject{disp: registered})
+		return err
+	})
+	return
+}
+
+// ExportAllTasksXML returns the XML of the definitions of all scheduled Tasks in Windows Task
+// Scheduler 2.0 by their path, including hidden Tasks, e.g. to back them up. The definitions
+// can be registered again with ImportTaskFromXML. Tasks whose XML can not be read and folders
+// that can not be enumerated are omitted and reported by a BatchError.
+func ExportAllTasksXML() (xmls map[string]string, err error) {
+	xmls = map[string]string{}
+	failed := BatchError{}
+	w := &walker{
+		ctx:          context.Background(),
+		hidden:       true,
+		metadataOnly: true, // only the XML of the definition is read
+		onTask: func(task dispatcher, t Task) error {
+			xml, err := definitionXML(task)
+			if err != nil {
+				failed[t.Path] = err
+				return nil
+			}
+			xmls[t.Path] = xml
+			return nil
+		},
+		onError: failed.add,
+	}
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		_, err := w.getTasks(ts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(failed) > 0 {
+		return xmls, failed
+	}
+	return xmls, nil
+}
+
+// definitionXML returns the XML of the definition of an IRegisteredTask object
+func definitionXML(registered dispatcher) (string, error) {
+	definition, err := getObject(registered, "definition")
+	if err != nil {
+		return "", &COMError{Op: "get definition of task in Task Scheduler 2.0", Err: err}
+	}
+	defer definition.Release()
+	value, err := definition.GetProperty("xmlText")
+	if err != nil {
+		return "", &COMError{Op: "get XML of task in Task Scheduler 2.0", Err: err}
+	}
+	xml, _ := value.(string)
+	return xml, nil
+}
+
+// ImportTaskFromXML registers the Task at path, e.g. "\\MyApp\\MyTask", from the XML of its
+// definition, e.g. exported by ExportTaskXML. An existing Task at path is overwritten. The Task
+// logs on like its principal in the XML, Tasks with password logon can not be imported this way
+// because the password is not part of the XML.
+func ImportTaskFromXML(path, xml string) (Task, error) {
+	return importTaskFromXML(path, xml, true, false)
+}
+
+// importTaskFromXML registers the Task at path from xml. ErrTaskExists is returned if the Task
+// already exists and overwrite is not set. The folder of the Task is created first if
+// createMissingFolder is set, so both are done with one connection.
+func importTaskFromXML(path, xml string, overwrite, createMissingFolder bool) (task Task, err error) {
+	if xml == "" {
+		return task, errors.New("Task XML is empty")
+	}
+	flags := taskCreate
+	if overwrite {
+		flags = taskCreateOrUpdate
+	}
+	folderPath, name := splitPath(path)
+	err = withTaskService(func(ts *ole.IDispatch) error {
+		if createMissingFolder {
+			if err := createFolder(ts, folderPath); err != nil {
+				return err
+			}
+		}
+		folder, err := getFolder(ts, folderPath)
+		if err == ErrFolderNotFound {
+			return fmt.Errorf("%w: %s", ErrFolderNotFound, folderPath)
+		} else if err != nil {
+			return err
+		}
+		defer folder.Release()
+		// Passing another logon type would override the principal of the XML
+		variant, err := oleutil.CallMethod(folder, "RegisterTask", name, xml, int32(flags), nil, nil, int32(xmlLogonType(xml)), nil)
+		if err != nil {
+			if hresult(err) == hresultAlreadyExists {
+				return ErrTaskExists
+			}
+			// The description contains the reason, e.g. the line of the XML that violates the schema
+			return &COMError{Op: "import task into Task Scheduler 2.0", Err: err}
+		}
+		registered := variant.ToIDispatch()
+		defer registered.Release()
+		task = parseTask(comObject{disp: registered})
+		return nil
+	})
+	return
+}